@@ -10,10 +10,13 @@ import (
 )
 
 func main() {
-	wnlm.Initialize()
-	defer wnlm.Uninitialize()
+	session, err := wnlm.NewSession()
+	if err != nil {
+		log.Fatalf("failed to initialize session: %v", err)
+	}
+	defer session.Close()
 
-	nlm, err := wnlm.NewNetworkListManager()
+	nlm, err := session.NewNetworkListManager()
 	if err != nil {
 		log.Fatalf("failed to initialize network list manager: %v", err)
 	}
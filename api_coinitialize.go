@@ -9,7 +9,12 @@ import (
 var globalOleConn ole.Connection = ole.Connection{}
 
 // Initialize initializes the COM connection for the current program.
+//
+// Deprecated: use NewSession, which is reference counted and lets
+// callers choose an apartment model.
 func Initialize() error { return globalOleConn.Initialize() }
 
 // Uninitialize uninitializes the COM connection for the current program.
+//
+// Deprecated: use NewSession and Session.Close.
 func Uninitialize() { globalOleConn.Uninitialize() }
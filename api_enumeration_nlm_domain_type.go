@@ -1,5 +1,3 @@
-//go:build windows
-
 package wnlm
 
 // NLMDomainType represents the NLM_DOMAIN_TYPE enumeration
@@ -1,5 +1,3 @@
-//go:build windows
-
 package wnlm
 
 // NLMNetworkCategory represents the NLM_NETWORK_CATEGORY enumeration
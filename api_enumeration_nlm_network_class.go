@@ -1,5 +1,3 @@
-//go:build windows
-
 package wnlm
 
 // NLMNetworkClass represents the NLM_NETWORK_CLASS enum (a set
@@ -0,0 +1,52 @@
+package wnlm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/adrianosela/wnlm/pkg/bits"
+)
+
+// NLMNetworkPropertyChange represents the NLM_NETWORK_PROPERTY_CHANGE enumeration
+// (a set of flags that specify which properties of a network have changed).
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/netlistmgr/ne-netlistmgr-nlm_network_property_change
+type NLMNetworkPropertyChange int32
+
+const (
+	// NLMNetworkPropertyChangeConnection represents a change to the connections of a network.
+	NLMNetworkPropertyChangeConnection = NLMNetworkPropertyChange(0x01)
+	// NLMNetworkPropertyChangeDescription represents a change to the description of a network.
+	NLMNetworkPropertyChangeDescription = NLMNetworkPropertyChange(0x02)
+	// NLMNetworkPropertyChangeName represents a change to the name of a network.
+	NLMNetworkPropertyChangeName = NLMNetworkPropertyChange(0x04)
+	// NLMNetworkPropertyChangeIcon represents a change to the icon of a network.
+	NLMNetworkPropertyChangeIcon = NLMNetworkPropertyChange(0x08)
+	// NLMNetworkPropertyChangeCategoryValue represents a change to the category of a network.
+	NLMNetworkPropertyChangeCategoryValue = NLMNetworkPropertyChange(0x10)
+)
+
+var nlmNetworkPropertyChangeToString = map[NLMNetworkPropertyChange]string{
+	NLMNetworkPropertyChangeConnection:    "Connection",
+	NLMNetworkPropertyChangeDescription:   "Description",
+	NLMNetworkPropertyChangeName:          "Name",
+	NLMNetworkPropertyChangeIcon:          "Icon",
+	NLMNetworkPropertyChangeCategoryValue: "CategoryValue",
+}
+
+// Has returns true if all the given flags are set on the NLMNetworkPropertyChange.
+func (c NLMNetworkPropertyChange) Has(flags NLMNetworkPropertyChange) bool {
+	return bits.AreSet(c, flags)
+}
+
+// String returns the string representation of the NLMNetworkPropertyChange.
+func (c NLMNetworkPropertyChange) String() string {
+	flags := []string{}
+	for flag, str := range nlmNetworkPropertyChangeToString {
+		if c.Has(flag) {
+			flags = append(flags, str)
+		}
+	}
+	sort.Strings(flags)
+	return strings.Join(flags, ", ")
+}
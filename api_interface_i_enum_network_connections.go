@@ -1,14 +1,5 @@
-//go:build windows
-
 package wnlm
 
-import (
-	"fmt"
-
-	"github.com/go-ole/go-ole"
-	"github.com/go-ole/go-ole/oleutil"
-)
-
 // IEnumNetworkConnections represents an enumeration of the Windows INetworkConnections type as defined in
 // https://learn.microsoft.com/en-us/windows/win32/api/netlistmgr/nn-netlistmgr-ienumnetworkconnections.
 //
@@ -24,25 +15,10 @@ type iEnumNetworkConnections struct {
 	conns []INetworkConnection
 }
 
-// NewNetworkConnections returns an IEnumNetworkConnections object based on an IDispatch object.
-func NewNetworkConnections(idispatch *ole.IDispatch) (IEnumNetworkConnections, error) {
-	conns := []INetworkConnection{}
-	err := oleutil.ForEach(idispatch, func(variant *ole.VARIANT) error {
-		networkConnection, err := NewNetworkConnectionFromVariant(variant)
-		if err != nil {
-			return fmt.Errorf("failed to convert variant to NetworkConnection interface: %v", err)
-		}
-		conns = append(conns, networkConnection)
-		return nil
-	})
-	if err != nil {
-		// release any connections we had already fetched successfully
-		for _, conn := range conns {
-			conn.Release()
-		}
-		return nil, fmt.Errorf("failed to convert variant to list of network connections: %v", err)
-	}
-	return &iEnumNetworkConnections{conns: conns}, nil
+// NewNetworkConnectionsFromSlice returns an IEnumNetworkConnections object holding the given
+// INetworkConnection objects, which are released when the IEnumNetworkConnections is released.
+func NewNetworkConnectionsFromSlice(conns []INetworkConnection) IEnumNetworkConnections {
+	return &iEnumNetworkConnections{conns: conns}
 }
 
 // ForEach iterates over each INetworkConnection represented by IEnumNetworkConnections.
@@ -0,0 +1,38 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"fmt"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// NewNetworkConnections returns an IEnumNetworkConnections object based on an IDispatch object.
+func NewNetworkConnections(idispatch *ole.IDispatch) (IEnumNetworkConnections, error) {
+	return newNetworkConnections(idispatch, false)
+}
+
+// newNetworkConnections returns an IEnumNetworkConnections object based on an IDispatch object,
+// optionally pre-resolving the DISPIDs of each INetworkConnection.
+func newNetworkConnections(idispatch *ole.IDispatch, preResolve bool) (IEnumNetworkConnections, error) {
+	conns := []INetworkConnection{}
+	err := oleutil.ForEach(idispatch, func(variant *ole.VARIANT) error {
+		networkConnection, err := newNetworkConnectionFromVariant(variant, preResolve)
+		if err != nil {
+			return fmt.Errorf("failed to convert variant to NetworkConnection interface: %w", err)
+		}
+		conns = append(conns, networkConnection)
+		return nil
+	})
+	if err != nil {
+		// release any connections we had already fetched successfully
+		for _, conn := range conns {
+			conn.Release()
+		}
+		return nil, fmt.Errorf("failed to convert variant to list of network connections: %w", hresult.Wrap(err))
+	}
+	return NewNetworkConnectionsFromSlice(conns), nil
+}
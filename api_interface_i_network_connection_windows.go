@@ -0,0 +1,132 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+	"github.com/go-ole/go-ole"
+)
+
+// iNetworkConnections is the default implementation of INetworkConnection.
+type iNetworkConnection struct {
+	idispatch  *ole.IDispatch
+	preResolve bool
+}
+
+// iNetworkConnectionVTable represents the INetworkConnection interface's VTable.
+type iNetworkConnectionVTable struct {
+	ole.IDispatchVtbl
+	GetNetwork            uintptr // id = 1, method
+	IsConnectedToInternet uintptr // id = 2, property
+	IsConnected           uintptr // id = 3, property
+	GetConnectivity       uintptr // id = 4, method
+	GetConnectionId       uintptr // id = 5, method
+	GetAdapterId          uintptr // id = 6, method
+	GetDomainType         uintptr // id = 7, method
+}
+
+// vtable returns the INetworkConnection's VTable.
+func (n *iNetworkConnection) vtable() *iNetworkConnectionVTable {
+	return (*iNetworkConnectionVTable)(unsafe.Pointer(n.idispatch.RawVTable))
+}
+
+// NewNetworkConnectionFromVariant returns the INetworkConnection object for a given ole.VARIANT.
+func NewNetworkConnectionFromVariant(variant *ole.VARIANT) (INetworkConnection, error) {
+	return newNetworkConnectionFromVariant(variant, false)
+}
+
+// newNetworkConnectionFromVariant returns the INetworkConnection object for a given
+// ole.VARIANT, optionally pre-resolving its DISPIDs.
+func newNetworkConnectionFromVariant(variant *ole.VARIANT, preResolve bool) (INetworkConnection, error) {
+	iUnknown := variant.ToIUnknown()
+	if iUnknown == nil {
+		return nil, fmt.Errorf("expected variant to be of VT type %d, but got %d", ole.VT_UNKNOWN, variant.VT)
+	}
+
+	idispatch, err := iUnknown.QueryInterface(iidINetworkConnection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use unknown interface as interface with GUID %s: %w", iidINetworkConnection.String(), hresult.Wrap(err))
+	}
+	if preResolve {
+		if err := preResolveDISPIDs(idispatch, iidINetworkConnection, iNetworkConnectionMembers); err != nil {
+			idispatch.Release()
+			return nil, fmt.Errorf("failed to pre-resolve DISPIDs for INetworkConnection: %w", err)
+		}
+	}
+	return &iNetworkConnection{idispatch: idispatch, preResolve: preResolve}, nil
+}
+
+// IsConnectedToInternet returns whether the network connection is connected to the Internet.
+func (n *iNetworkConnection) IsConnectedToInternet() (bool, error) {
+	isConnectedToInternet, err := getVariantBool(n.idispatch, iidINetworkConnection, n.vtable().IsConnectedToInternet, "IsConnectedToInternet")
+	if err != nil {
+		return false, fmt.Errorf("failed to get IsConnectedToInternet property: %w", err)
+	}
+	return isConnectedToInternet, nil
+}
+
+// IsConnected returns whether the network connection is connected.
+func (n *iNetworkConnection) IsConnected() (bool, error) {
+	isConnected, err := getVariantBool(n.idispatch, iidINetworkConnection, n.vtable().IsConnected, "IsConnected")
+	if err != nil {
+		return false, fmt.Errorf("failed to get IsConnected property: %w", err)
+	}
+	return isConnected, nil
+}
+
+// GetNetwork returns the INetwork for a network connection.
+func (nc *iNetworkConnection) GetNetwork() (INetwork, error) {
+	idispatch, err := getIDispatch(nc.idispatch, iidINetworkConnection, nc.vtable().GetNetwork, "GetNetwork")
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GetNetwork method: %w", err)
+	}
+	network, err := newINetwork(idispatch, nc.preResolve)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get INetwork for network connection: %w", err)
+	}
+	return network, nil
+}
+
+// GetConnectivity gets the connectivity of this network connection.
+func (n *iNetworkConnection) GetConnectivity() (NLMConnectivity, error) {
+	connectivity, err := getInt32(n.idispatch, iidINetworkConnection, n.vtable().GetConnectivity, "GetConnectivity")
+	if err != nil {
+		return -1, fmt.Errorf("failed to call GetConnectivity method: %w", err)
+	}
+	return NLMConnectivity(connectivity), nil
+}
+
+// GetConnectionId returns the connection GUID for a network connection.
+func (nc *iNetworkConnection) GetConnectionId() (GUID, error) {
+	guid, err := getGUID(nc.idispatch, nc.vtable().GetConnectionId)
+	if err != nil {
+		return GUID{}, fmt.Errorf("failed to get connection id for network connection: %w", err)
+	}
+	return guid, nil
+}
+
+// GetAdapterId returns the adapter GUID for a network connection.
+func (nc *iNetworkConnection) GetAdapterId() (GUID, error) {
+	guid, err := getGUID(nc.idispatch, nc.vtable().GetAdapterId)
+	if err != nil {
+		return GUID{}, fmt.Errorf("failed to get adapter id for network connection: %w", err)
+	}
+	return guid, nil
+}
+
+// GetDomainType gets the domain type of this network connection.
+func (n *iNetworkConnection) GetDomainType() (NLMDomainType, error) {
+	domainType, err := getInt32(n.idispatch, iidINetworkConnection, n.vtable().GetDomainType, "GetDomainType")
+	if err != nil {
+		return -1, fmt.Errorf("failed to call GetDomainType method: %w", err)
+	}
+	return NLMDomainType(domainType), nil
+}
+
+// Release releases the INetworkConnection object.
+func (nc *iNetworkConnection) Release() {
+	nc.idispatch.Release()
+}
@@ -0,0 +1,84 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+	"github.com/go-ole/go-ole"
+)
+
+// iNetworkListManager is the default implementation of INetworkListManager.
+type iNetworkListManager struct {
+	idispatch  *ole.IDispatch
+	preResolve bool
+}
+
+// iNetworkListManagerVtbl represents the INetworkListManager interface's VTable.
+type iNetworkListManagerVtbl struct {
+	ole.IDispatchVtbl
+	GetNetworks               uintptr // id = 1, method
+	GetNetwork                uintptr // id = 2, method
+	GetNetworkConnections     uintptr // id = 3, method
+	GetNetworkConnection      uintptr // id = 4, method
+	IsConnectedToInternet     uintptr // id = 5, property
+	IsConnected               uintptr // id = 6, property
+	GetConnectivity           uintptr // id = 7, method
+	SetSimulatedProfileInfo   uintptr // id = 8, method
+	ClearSimulatedProfileInfo uintptr // id = 9, method
+}
+
+// vtable returns the INetworkListManager's VTable.
+func (nlm *iNetworkListManager) vtable() *iNetworkListManagerVtbl {
+	return (*iNetworkListManagerVtbl)(unsafe.Pointer(nlm.idispatch.RawVTable))
+}
+
+// NewNetworkListManager initializes a new Windows NetworkListManager API client.
+func NewNetworkListManager(opts ...ManagerOption) (INetworkListManager, error) {
+	options := newManagerOptions(opts...)
+
+	// NOTE(@adrianosela): DCB00C01-570F-4A9B-8D69-199FDBA5723B is the
+	// well-known Windows Global ID for the NetworkListManager class ID.
+	unknown, err := ole.CreateInstance(ole.NewGUID("{DCB00C01-570F-4A9B-8D69-199FDBA5723B}"), ole.IID_IUnknown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NetworkListManager object by class ID: %w", hresult.Wrap(err))
+	}
+	defer unknown.Release()
+
+	// NOTE(@adrianosela): querying for the INetworkListManager interface (rather
+	// than for IDispatch) guarantees that the object's VTable is that of INetworkListManager.
+	idispatch, err := unknown.QueryInterface(iidINetworkListManager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispatch object for NetworkListManager: %w", hresult.Wrap(err))
+	}
+	if options.preResolveDISPIDs {
+		if err := preResolveDISPIDs(idispatch, iidINetworkListManager, iNetworkListManagerMembers); err != nil {
+			idispatch.Release()
+			return nil, fmt.Errorf("failed to pre-resolve DISPIDs for NetworkListManager: %w", err)
+		}
+	}
+	return &iNetworkListManager{idispatch: idispatch, preResolve: options.preResolveDISPIDs}, nil
+}
+
+// GetNetworkConnections returns all network connections for the system by using the API call described in
+// https://learn.microsoft.com/en-us/windows/win32/api/netlistmgr/nf-netlistmgr-inetwork-getnetworkconnections.
+func (nlm *iNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	idispatch, err := getIDispatch(nlm.idispatch, iidINetworkListManager, nlm.vtable().GetNetworkConnections, "GetNetworkConnections")
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GetNetworkConnections on NetworkListManager object: %w", err)
+	}
+	defer idispatch.Release()
+
+	networkConnections, err := newNetworkConnections(idispatch, nlm.preResolve)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NetworkConnections from *ole.VARIANT: %w", err)
+	}
+	return networkConnections, nil
+}
+
+// Release releases the NetworkListManager object.
+func (nlm *iNetworkListManager) Release() {
+	nlm.idispatch.Release()
+}
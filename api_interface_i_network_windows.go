@@ -0,0 +1,193 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+	"github.com/adrianosela/wnlm/pkg/wintime"
+	"github.com/go-ole/go-ole"
+)
+
+// iNetwork is the default implementation of INetwork.
+type iNetwork struct {
+	idispatch  *ole.IDispatch
+	preResolve bool
+}
+
+type iNetworkVtbl struct {
+	ole.IDispatchVtbl
+	GetName                    uintptr // id = 1, method
+	SetName                    uintptr // id = 2, method
+	GetDescription             uintptr // id = 3, method
+	SetDescription             uintptr // id = 4, method
+	GetNetworkId               uintptr // id = 5, method
+	GetDomainType              uintptr // id = 6, method
+	GetNetworkConnections      uintptr // id = 7, method
+	GetTimeCreatedAndConnected uintptr // id = 8, method
+	IsConnectedToInternet      uintptr // id = 9, property
+	IsConnected                uintptr // id = 10, property
+	GetConnectivity            uintptr // id = 11, method
+	GetCategory                uintptr // id = 12, method
+	SetCategory                uintptr // id = 13, method
+}
+
+// vtable returns the INetwork's VTable.
+func (n *iNetwork) vtable() *iNetworkVtbl {
+	return (*iNetworkVtbl)(unsafe.Pointer(n.idispatch.RawVTable))
+}
+
+// INetworkFromIDispatch returns an INetwork based on its IDispatch.
+func INetworkFromIDispatch(idispatch *ole.IDispatch) INetwork {
+	return &iNetwork{idispatch: idispatch}
+}
+
+// newINetwork returns an INetwork based on its IDispatch, optionally pre-resolving its
+// DISPIDs. The IDispatch is released if pre-resolution fails.
+func newINetwork(idispatch *ole.IDispatch, preResolve bool) (INetwork, error) {
+	if preResolve {
+		if err := preResolveDISPIDs(idispatch, iidINetwork, iNetworkMembers); err != nil {
+			idispatch.Release()
+			return nil, fmt.Errorf("failed to pre-resolve DISPIDs for INetwork: %w", err)
+		}
+	}
+	return &iNetwork{idispatch: idispatch, preResolve: preResolve}, nil
+}
+
+// GetName gets the name of this network.
+func (n *iNetwork) GetName() (string, error) {
+	name, err := getBSTR(n.idispatch, iidINetwork, n.vtable().GetName, "GetName")
+	if err != nil {
+		return "", fmt.Errorf("failed to call GetName method: %w", err)
+	}
+	return name, nil
+}
+
+// SetName sets the name of this network.
+func (n *iNetwork) SetName(name string) error {
+	if err := putBSTR(n.idispatch, iidINetwork, n.vtable().SetName, "SetName", name); err != nil {
+		return fmt.Errorf("failed to call SetName method with value %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetDescription gets the description/alias of this network.
+func (n *iNetwork) GetDescription() (string, error) {
+	descr, err := getBSTR(n.idispatch, iidINetwork, n.vtable().GetDescription, "GetDescription")
+	if err != nil {
+		return "", fmt.Errorf("failed to call GetDescription method: %w", err)
+	}
+	return descr, nil
+}
+
+// SetDescription sets the description/alias of this network.
+func (n *iNetwork) SetDescription(descr string) error {
+	if err := putBSTR(n.idispatch, iidINetwork, n.vtable().SetDescription, "SetDescription", descr); err != nil {
+		return fmt.Errorf("failed to call SetDescription method with value %s: %w", descr, err)
+	}
+	return nil
+}
+
+// GetNetworkId returns the GUID of this network.
+func (n *iNetwork) GetNetworkId() (GUID, error) {
+	guid, err := getGUID(n.idispatch, n.vtable().GetNetworkId)
+	if err != nil {
+		return GUID{}, fmt.Errorf("failed to call GetNetworkId method: %w", err)
+	}
+	return guid, nil
+}
+
+// GetDomainType gets the domain type of this network.
+func (n *iNetwork) GetDomainType() (NLMDomainType, error) {
+	domainType, err := getInt32(n.idispatch, iidINetwork, n.vtable().GetDomainType, "GetDomainType")
+	if err != nil {
+		return -1, fmt.Errorf("failed to call GetDomainType method: %w", err)
+	}
+	return NLMDomainType(domainType), nil
+}
+
+// GetNetworkConnections returns the network connections for this network.
+func (n *iNetwork) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	idispatch, err := getIDispatch(n.idispatch, iidINetwork, n.vtable().GetNetworkConnections, "GetNetworkConnections")
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GetNetworkConnections on INetwork object: %w", err)
+	}
+	defer idispatch.Release()
+	networkConnections, err := newNetworkConnections(idispatch, n.preResolve)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NetworkConnections from *ole.VARIANT: %w", err)
+	}
+	return networkConnections, nil
+}
+
+// GetTimeCreatedAndConnected gets the timestamps of this network being created and connected.
+// Timestamps which are not set by Windows are returned as the zero time.Time.
+func (n *iNetwork) GetTimeCreatedAndConnected() (time.Time, time.Time, error) {
+	var created, connected wintime.FILETIME
+	hr := vtableCall(
+		n.idispatch,
+		n.vtable().GetTimeCreatedAndConnected,
+		uintptr(unsafe.Pointer(&created.LowDateTime)),
+		uintptr(unsafe.Pointer(&created.HighDateTime)),
+		uintptr(unsafe.Pointer(&connected.LowDateTime)),
+		uintptr(unsafe.Pointer(&connected.HighDateTime)),
+	)
+	if err := hresult.Check(hr); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to call GetTimeCreatedAndConnected method: %w", err)
+	}
+	createdTime, _ := created.Time()
+	connectedTime, _ := connected.Time()
+	return createdTime, connectedTime, nil
+}
+
+// IsConnectedToInternet returns whether the network is connected to the Internet.
+func (n *iNetwork) IsConnectedToInternet() (bool, error) {
+	isConnectedToInternet, err := getVariantBool(n.idispatch, iidINetwork, n.vtable().IsConnectedToInternet, "IsConnectedToInternet")
+	if err != nil {
+		return false, fmt.Errorf("failed to get IsConnectedToInternet property: %w", err)
+	}
+	return isConnectedToInternet, nil
+}
+
+// IsConnected returns whether the network is connected.
+func (n *iNetwork) IsConnected() (bool, error) {
+	isConnected, err := getVariantBool(n.idispatch, iidINetwork, n.vtable().IsConnected, "IsConnected")
+	if err != nil {
+		return false, fmt.Errorf("failed to get IsConnected property: %w", err)
+	}
+	return isConnected, nil
+}
+
+// GetConnectivity gets the connectivity of this network.
+func (n *iNetwork) GetConnectivity() (NLMConnectivity, error) {
+	connectivity, err := getInt32(n.idispatch, iidINetwork, n.vtable().GetConnectivity, "GetConnectivity")
+	if err != nil {
+		return -1, fmt.Errorf("failed to call GetConnectivity method: %w", err)
+	}
+	return NLMConnectivity(connectivity), nil
+}
+
+// GetCategory gets the category of this network.
+func (n *iNetwork) GetCategory() (NLMNetworkCategory, error) {
+	category, err := getInt32(n.idispatch, iidINetwork, n.vtable().GetCategory, "GetCategory")
+	if err != nil {
+		return -1, fmt.Errorf("failed to call GetCategory method: %w", err)
+	}
+	return NLMNetworkCategory(category), nil
+}
+
+// SetCategory sets the category of this network.
+func (n *iNetwork) SetCategory(category NLMNetworkCategory) error {
+	if err := putInt32(n.idispatch, iidINetwork, n.vtable().SetCategory, "SetCategory", int32(category)); err != nil {
+		return fmt.Errorf("failed to call SetCategory method with value %d: %w", int32(category), err)
+	}
+	return nil
+}
+
+// Release releases the INetwork object.
+func (n *iNetwork) Release() {
+	n.idispatch.Release()
+}
@@ -0,0 +1,322 @@
+package wnlm
+
+import (
+	"sync"
+	"time"
+)
+
+// immutableMethods are the getters whose results never change for a given object.
+var immutableMethods = map[string]bool{
+	"GetNetworkId":    true,
+	"GetConnectionId": true,
+	"GetAdapterId":    true,
+}
+
+// cacheableMethods are the getters whose results can be cached.
+var cacheableMethods = map[string]bool{
+	"GetName":                    true,
+	"GetDescription":             true,
+	"GetNetworkId":               true,
+	"GetConnectionId":            true,
+	"GetAdapterId":               true,
+	"GetDomainType":              true,
+	"GetTimeCreatedAndConnected": true,
+	"IsConnectedToInternet":      true,
+	"IsConnected":                true,
+	"GetConnectivity":            true,
+	"GetCategory":                true,
+}
+
+// connectivityMethods are the getters whose results depend on connectivity.
+var connectivityMethods = []string{"GetConnectivity", "IsConnected", "IsConnectedToInternet"}
+
+// setterInvalidates maps setters to the getters whose results they change.
+var setterInvalidates = map[string]string{
+	"SetName":        "GetName",
+	"SetDescription": "GetDescription",
+	"SetCategory":    "GetCategory",
+}
+
+// CacheStats are the statistics of a PropertyCache.
+type CacheStats struct {
+	// Hits is the number of calls served from the cache.
+	Hits uint64
+	// Misses is the number of cacheable calls which were not served from the cache.
+	Misses uint64
+	// Invalidations is the number of cached results discarded before expiring.
+	Invalidations uint64
+}
+
+// CacheOption configures a PropertyCache created with NewPropertyCache.
+type CacheOption func(*cacheOptions)
+
+// cacheOptions holds the configuration set by CacheOptions.
+type cacheOptions struct {
+	defaultTTL time.Duration
+	ttls       map[string]time.Duration
+	now        func() time.Time
+}
+
+// WithDefaultTTL sets how long results are cached for, unless set
+// otherwise with WithPropertyTTL. The default is 5 seconds.
+func WithDefaultTTL(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) { o.defaultTTL = ttl }
+}
+
+// WithPropertyTTL sets how long the results of the given getter (e.g. "GetName") are
+// cached for, overriding the default TTL. A TTL of zero (or less) disables caching it.
+// GetNetworkId, GetConnectionId and GetAdapterId never change, so they are always cached.
+func WithPropertyTTL(method string, ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) { o.ttls[method] = ttl }
+}
+
+// WithClock sets the function used to tell the time. The default is time.Now.
+func WithClock(now func() time.Time) CacheOption {
+	return func(o *cacheOptions) { o.now = now }
+}
+
+// cacheKey identifies a cached result.
+type cacheKey struct {
+	iface  string
+	id     GUID
+	method string
+}
+
+// cacheEntry is a cached result.
+type cacheEntry struct {
+	results []any
+	expires time.Time
+}
+
+// PropertyCache caches the results of the getters of INetwork and INetworkConnection
+// objects, by the GUID of the object, for a time-to-live set per getter. Its Interceptor
+// serves cached results without calling the underlying objects, and discards those
+// changed by Set* calls made through it. Cached results can also be discarded as
+// changes happen, by passing Events to HandleEvent.
+//
+// Getters returning COM objects (GetNetwork and GetNetworkConnections) are never cached.
+type PropertyCache struct {
+	options cacheOptions
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	ids     map[any]GUID
+	stats   CacheStats
+}
+
+// NewPropertyCache returns a new, empty, PropertyCache.
+func NewPropertyCache(opts ...CacheOption) *PropertyCache {
+	options := cacheOptions{defaultTTL: 5 * time.Second, ttls: make(map[string]time.Duration), now: time.Now}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &PropertyCache{
+		options: options,
+		entries: make(map[cacheKey]cacheEntry),
+		ids:     make(map[any]GUID),
+	}
+}
+
+// NewCachingNetworkListManager returns an INetworkListManager which serves the
+// properties of the networks and network connections obtained from nlm from cache.
+// Releasing the returned INetworkListManager releases nlm.
+func NewCachingNetworkListManager(nlm INetworkListManager, cache *PropertyCache) INetworkListManager {
+	return InterceptNetworkListManager(nlm, cache.Interceptor)
+}
+
+// Interceptor is an Interceptor (see InterceptNetworkListManager) serving calls from the cache.
+func (c *PropertyCache) Interceptor(call *Call, next Invoker) {
+	switch {
+	case call.Method == "Release":
+		released := releasedObjects(call.Target)
+		next(call)
+		c.mu.Lock()
+		for _, obj := range released {
+			delete(c.ids, obj)
+		}
+		c.mu.Unlock()
+	case setterInvalidates[call.Method] != "":
+		next(call)
+		if id, ok := c.targetID(call); ok {
+			c.invalidate(func(key cacheKey) bool { return key.id == id && key.method == setterInvalidates[call.Method] })
+		}
+	case cacheableMethods[call.Method] && c.ttl(call.Method) > 0:
+		c.cached(call, next)
+	default:
+		next(call)
+	}
+}
+
+// cached serves a call from the cache, or makes it and caches its results.
+func (c *PropertyCache) cached(call *Call, next Invoker) {
+	id, ok := c.targetID(call)
+	if !ok {
+		next(call)
+		return
+	}
+	key := cacheKey{iface: call.Interface, id: id, method: call.Method}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && (entry.expires.IsZero() || c.options.now().Before(entry.expires)) {
+		c.stats.Hits++
+		c.mu.Unlock()
+		call.Results = append([]any(nil), entry.results...)
+		return
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	next(call)
+	if call.Err != nil {
+		return
+	}
+	now := c.options.now()
+	entry = cacheEntry{results: append([]any(nil), call.Results...)}
+	if !immutableMethods[call.Method] {
+		entry.expires = now.Add(c.ttl(call.Method))
+	}
+	c.mu.Lock()
+	c.evictExpired(now)
+	c.entries[key] = entry
+	c.mu.Unlock()
+}
+
+// evictExpired discards the cached results which expired by the given time.
+// It must be called with c.mu held.
+func (c *PropertyCache) evictExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// ttl returns the TTL of the results of a getter.
+func (c *PropertyCache) ttl(method string) time.Duration {
+	if immutableMethods[method] {
+		return time.Duration(1<<63 - 1)
+	}
+	if ttl, ok := c.options.ttls[method]; ok {
+		return ttl
+	}
+	return c.options.defaultTTL
+}
+
+// targetID returns the GUID of the object a call is made on, looking it up the first time.
+func (c *PropertyCache) targetID(call *Call) (GUID, bool) {
+	c.mu.Lock()
+	id, ok := c.ids[call.Target]
+	c.mu.Unlock()
+	if ok {
+		return id, true
+	}
+
+	var err error
+	switch target := call.Target.(type) {
+	case INetwork:
+		id, err = target.GetNetworkId()
+	case INetworkConnection:
+		id, err = target.GetConnectionId()
+	default:
+		return GUID{}, false
+	}
+	if err != nil {
+		return GUID{}, false
+	}
+	c.mu.Lock()
+	c.ids[call.Target] = id
+	c.mu.Unlock()
+	return id, true
+}
+
+// invalidate discards the cached results matching the given filter.
+func (c *PropertyCache) invalidate(match func(cacheKey) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpired(c.options.now())
+	for key := range c.entries {
+		if !immutableMethods[key.method] && match(key) {
+			delete(c.entries, key)
+			c.stats.Invalidations++
+		}
+	}
+}
+
+// Invalidate discards every cached result of the network or network connection with the given GUID.
+func (c *PropertyCache) Invalidate(id GUID) {
+	c.invalidate(func(key cacheKey) bool { return key.id == id })
+}
+
+// forget discards every cached result of a deleted network or network connection,
+// including those which never change.
+func (c *PropertyCache) forget(id GUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.id == id {
+			delete(c.entries, key)
+			if !immutableMethods[key.method] {
+				c.stats.Invalidations++
+			}
+		}
+	}
+}
+
+// InvalidateAll discards every cached result.
+func (c *PropertyCache) InvalidateAll() {
+	c.invalidate(func(cacheKey) bool { return true })
+}
+
+// HandleEvent discards the cached results made stale by an Event. Since a change in the
+// connectivity of a network changes that of its connections (and vice versa), connectivity
+// changes discard the cached connectivity of every network and network connection.
+func (c *PropertyCache) HandleEvent(event Event) {
+	switch event.Kind {
+	case EventNetworkDeleted, EventConnectionDeleted:
+		c.forget(event.ID)
+	case EventConnectionPropertyChanged:
+		c.Invalidate(event.ID)
+	case EventNetworkConnectivityChanged, EventConnectionConnectivityChanged:
+		c.invalidate(func(key cacheKey) bool { return isConnectivityMethod(key.method) })
+	case EventNetworkPropertyChanged:
+		c.invalidate(func(key cacheKey) bool {
+			if key.id != event.ID {
+				return false
+			}
+			switch key.method {
+			case "GetName":
+				return event.Properties.Has(NLMNetworkPropertyChangeName)
+			case "GetDescription":
+				return event.Properties.Has(NLMNetworkPropertyChangeDescription)
+			case "GetCategory":
+				return event.Properties.Has(NLMNetworkPropertyChangeCategoryValue)
+			default:
+				return event.Properties.Has(NLMNetworkPropertyChangeConnection) &&
+					(isConnectivityMethod(key.method) || key.method == "GetTimeCreatedAndConnected")
+			}
+		})
+	}
+}
+
+// Subscribe discards cached results as Events are delivered by source, until the returned function is called.
+func (c *PropertyCache) Subscribe(source EventSource) (unsubscribe func()) {
+	return source.Subscribe(c.HandleEvent)
+}
+
+// Stats returns the statistics of the PropertyCache.
+func (c *PropertyCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// isConnectivityMethod returns true if the results of the given getter depend on connectivity.
+func isConnectivityMethod(method string) bool {
+	for _, connectivityMethod := range connectivityMethods {
+		if method == connectivityMethod {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package wnlm
+
+import (
+	"testing"
+	"time"
+)
+
+// sequentialNetworkListManager is an INetworkListManager whose network
+// connections have a new GUID every time they are enumerated.
+type sequentialNetworkListManager struct {
+	next uint32
+}
+
+func (m *sequentialNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	m.next++
+	return NewNetworkConnectionsFromSlice([]INetworkConnection{&stubConnection{id: GUID{Data1: m.next}}}), nil
+}
+
+func (m *sequentialNetworkListManager) Release() {}
+
+func TestPropertyCacheForgetsReleasedAndExpired(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := NewPropertyCache(WithDefaultTTL(time.Second), WithClock(func() time.Time { return now }))
+	nlm := NewCachingNetworkListManager(&sequentialNetworkListManager{}, c)
+	for i := 0; i < 10; i++ {
+		conns, err := nlm.GetNetworkConnections()
+		if err != nil {
+			t.Fatalf("GetNetworkConnections failed: %v", err)
+		}
+		conns.ForEach(func(_ int, conn INetworkConnection) bool {
+			conn.IsConnected()
+			return true
+		})
+		// connections are released along with their enumeration
+		conns.Release()
+		now = now.Add(time.Second)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := len(c.ids); n != 0 {
+		t.Errorf("%d GUIDs still known once every object was released, want 0", n)
+	}
+	if n := len(c.entries); n != 1 {
+		t.Errorf("%d results cached, want only the last one which has not expired", n)
+	}
+}
@@ -0,0 +1,142 @@
+package wnlm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+// testClock is a clock advanced by hand.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+// cachedNetwork returns the cache, the clock it uses, and the home network obtained through a caching manager over m.
+func cachedNetwork(t *testing.T, m *fake.NetworkListManager, opts ...wnlm.CacheOption) (*wnlm.PropertyCache, *testClock, wnlm.INetwork) {
+	t.Helper()
+	clock := &testClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	cache := wnlm.NewPropertyCache(append([]wnlm.CacheOption{wnlm.WithClock(clock.Now)}, opts...)...)
+	nlm := wnlm.NewCachingNetworkListManager(m, cache)
+	t.Cleanup(nlm.Release)
+
+	conns, conn := firstConnection(t, nlm)
+	t.Cleanup(conns.Release)
+	network, err := conn.GetNetwork()
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	t.Cleanup(network.Release)
+	return cache, clock, network
+}
+
+// assertName fails the test if the name of network is not want.
+func assertName(t *testing.T, network wnlm.INetwork, want string) {
+	t.Helper()
+	if name, err := network.GetName(); err != nil || name != want {
+		t.Errorf("GetName = %q, %v, want %q", name, err, want)
+	}
+}
+
+func TestPropertyCacheTTL(t *testing.T) {
+	m := fake.NewSample()
+	cache, clock, network := cachedNetwork(t, m, wnlm.WithPropertyTTL("GetName", time.Minute))
+
+	assertName(t, network, "Home")
+	m.UpdateNetwork(fake.SampleHomeID, func(n *fake.Network) { n.Name = "Renamed" })
+	clock.now = clock.now.Add(time.Minute - time.Nanosecond)
+	assertName(t, network, "Home")
+	clock.now = clock.now.Add(time.Nanosecond)
+	assertName(t, network, "Renamed")
+
+	if got, want := cache.Stats(), (wnlm.CacheStats{Hits: 1, Misses: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPropertyCacheDisabledProperty(t *testing.T) {
+	m := fake.NewSample()
+	cache, _, network := cachedNetwork(t, m, wnlm.WithPropertyTTL("GetName", 0))
+
+	assertName(t, network, "Home")
+	m.UpdateNetwork(fake.SampleHomeID, func(n *fake.Network) { n.Name = "Renamed" })
+	assertName(t, network, "Renamed")
+	if got := cache.Stats(); got != (wnlm.CacheStats{}) {
+		t.Errorf("Stats() = %+v, want no hits nor misses", got)
+	}
+}
+
+func TestPropertyCacheSetterInvalidates(t *testing.T) {
+	m := fake.NewSample()
+	cache, _, network := cachedNetwork(t, m)
+
+	assertName(t, network, "Home")
+	if _, err := network.GetCategory(); err != nil {
+		t.Fatalf("GetCategory failed: %v", err)
+	}
+	if err := network.SetName("Renamed"); err != nil {
+		t.Fatalf("SetName failed: %v", err)
+	}
+	assertName(t, network, "Renamed")
+	if category, err := network.GetCategory(); err != nil || category != wnlm.NLMNetworkCategoryPrivate {
+		t.Errorf("GetCategory = %s, %v, want private", category, err)
+	}
+
+	if got, want := cache.Stats(), (wnlm.CacheStats{Hits: 1, Misses: 3, Invalidations: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPropertyCacheHandleEvent(t *testing.T) {
+	m := fake.NewSample()
+	cache, _, network := cachedNetwork(t, m)
+
+	assertName(t, network, "Home")
+	if _, err := network.GetCategory(); err != nil {
+		t.Fatalf("GetCategory failed: %v", err)
+	}
+	if _, err := network.GetConnectivity(); err != nil {
+		t.Fatalf("GetConnectivity failed: %v", err)
+	}
+	m.UpdateNetwork(fake.SampleHomeID, func(n *fake.Network) {
+		n.Name = "Renamed"
+		n.Category = wnlm.NLMNetworkCategoryPublic
+		n.Connectivity = wnlm.NLMConnectivityDisconnected
+	})
+
+	cache.HandleEvent(wnlm.Event{Kind: wnlm.EventNetworkPropertyChanged, ID: fake.SampleHomeID, Properties: wnlm.NLMNetworkPropertyChangeName})
+	assertName(t, network, "Renamed")
+	if category, _ := network.GetCategory(); category != wnlm.NLMNetworkCategoryPrivate {
+		t.Errorf("GetCategory = %s, want the cached private category", category)
+	}
+
+	// connectivity changes of connections discard that of networks too
+	cache.HandleEvent(wnlm.Event{Kind: wnlm.EventConnectionConnectivityChanged, ID: fake.SampleWiFiID})
+	if connectivity, _ := network.GetConnectivity(); connectivity != wnlm.NLMConnectivityDisconnected {
+		t.Errorf("GetConnectivity = %s, want disconnected", connectivity)
+	}
+
+	cache.HandleEvent(wnlm.Event{Kind: wnlm.EventNetworkDeleted, ID: fake.SampleHomeID})
+	if category, _ := network.GetCategory(); category != wnlm.NLMNetworkCategoryPublic {
+		t.Errorf("GetCategory = %s, want public", category)
+	}
+
+	if got, want := cache.Stats(), (wnlm.CacheStats{Hits: 1, Misses: 6, Invalidations: 5}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPropertyCacheInvalidate(t *testing.T) {
+	m := fake.NewSample()
+	cache, _, network := cachedNetwork(t, m)
+
+	assertName(t, network, "Home")
+	m.UpdateNetwork(fake.SampleHomeID, func(n *fake.Network) { n.Name = "Renamed" })
+	cache.Invalidate(fake.SampleOfficeID)
+	assertName(t, network, "Home")
+	cache.InvalidateAll()
+	assertName(t, network, "Renamed")
+}
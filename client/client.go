@@ -0,0 +1,129 @@
+// Package client implements the wnlm interfaces over the HTTP API served by the httpapi
+// package, such that code written against them can inspect the networks of a machine
+// remotely, from any platform.
+//
+// The objects returned hold the state of a network (or network connection) at the time
+// they were obtained, as served by the API, and their Set* methods fail with a
+// *wnlm.ReadOnlyError. Releasing them is a no-op.
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/httpapi"
+)
+
+// Option represents a configuration option for a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used to make requests. The default
+// is an http.Client with a timeout of 30 seconds.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// Client is a wnlm.INetworkListManager inspecting the networks of a machine through its HTTP API.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+var _ wnlm.INetworkListManager = (*Client)(nil)
+
+// New returns a Client of the HTTP API at the given base URL, e.g. "http://host:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	c := &Client{baseURL: u, httpClient: &http.Client{Timeout: 30 * time.Second}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// GetNetworkConnections returns the network connections of the machine.
+func (c *Client) GetNetworkConnections() (wnlm.IEnumNetworkConnections, error) {
+	return c.networkConnections(func(wnlm.ConnectionInfo) bool { return true })
+}
+
+// Release releases the Client, which is a no-op.
+func (c *Client) Release() {}
+
+// Connectivity returns the overall connectivity of the machine.
+func (c *Client) Connectivity() (httpapi.Connectivity, error) {
+	var connectivity httpapi.Connectivity
+	err := c.get(&connectivity, "connectivity")
+	return connectivity, err
+}
+
+// Network returns the network with the given GUID, failing with hresult.E_NOT_FOUND if there is none.
+func (c *Client) Network(id wnlm.GUID) (wnlm.INetwork, error) {
+	var info wnlm.NetworkInfo
+	if err := c.get(&info, "networks", id.String()); err != nil {
+		return nil, err
+	}
+	return &network{client: c, info: info}, nil
+}
+
+// NetworkConnection returns the network connection with the given GUID, failing with
+// hresult.E_NOT_FOUND if there is none.
+func (c *Client) NetworkConnection(id wnlm.GUID) (wnlm.INetworkConnection, error) {
+	var info wnlm.ConnectionInfo
+	if err := c.get(&info, "connections", id.String()); err != nil {
+		return nil, err
+	}
+	return &networkConnection{client: c, info: info}, nil
+}
+
+// networkConnections returns the network connections for which match returns true.
+func (c *Client) networkConnections(match func(wnlm.ConnectionInfo) bool) (wnlm.IEnumNetworkConnections, error) {
+	var infos []wnlm.ConnectionInfo
+	if err := c.get(&infos, "connections"); err != nil {
+		return nil, err
+	}
+	conns := []wnlm.INetworkConnection{}
+	for _, info := range infos {
+		if match(info) {
+			conns = append(conns, &networkConnection{client: c, info: info})
+		}
+	}
+	return wnlm.NewNetworkConnectionsFromSlice(conns), nil
+}
+
+// get gets the resource at the given path (relative to the base URL) and decodes it into v.
+// Requests answered with an httpapi.Error fail with it.
+func (c *Client) get(v any, path ...string) error {
+	u := c.baseURL.JoinPath(path...)
+	resp, err := c.httpClient.Get(u.String())
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", u.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &httpapi.Error{}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			return fmt.Errorf("failed to get %s: %s", u.Path, resp.Status)
+		}
+		return fmt.Errorf("failed to get %s: %w", u.Path, apiErr)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("failed to decode %s: %w", u.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/client"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/httpapi"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// newTestClient returns a Client of the API served for a fake holding testState, and the fake.
+func newTestClient(t *testing.T) (*client.Client, *fake.NetworkListManager) {
+	t.Helper()
+	m := fake.NewSample()
+	server := httptest.NewServer(httpapi.NewHandler(m))
+	t.Cleanup(server.Close)
+	c, err := client.New(server.URL)
+	if err != nil {
+		t.Fatalf("New(%q) failed: %v", server.URL, err)
+	}
+	return c, m
+}
+
+func TestClientSnapshot(t *testing.T) {
+	c, m := newTestClient(t)
+	want, err := wnlm.TakeSnapshot(m)
+	if err != nil {
+		t.Fatalf("TakeSnapshot(fake) failed: %v", err)
+	}
+	got, err := wnlm.TakeSnapshot(c)
+	if err != nil {
+		t.Fatalf("TakeSnapshot(client) failed: %v", err)
+	}
+	got.Time = want.Time // when the snapshots were taken
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TakeSnapshot(client) = %+v, want %+v", got, want)
+	}
+}
+
+func TestClientNetwork(t *testing.T) {
+	c, _ := newTestClient(t)
+	network, err := c.Network(fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("Network failed: %v", err)
+	}
+	defer network.Release()
+
+	if id, err := network.GetNetworkId(); err != nil || id != fake.SampleHomeID {
+		t.Errorf("GetNetworkId() = %v, %v, want %v", id, err, fake.SampleHomeID)
+	}
+	if name, err := network.GetName(); err != nil || name != "Home" {
+		t.Errorf("GetName() = %q, %v, want Home", name, err)
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if c, conn, err := network.GetTimeCreatedAndConnected(); err != nil || !c.Equal(created) || !conn.Equal(created.Add(time.Hour)) {
+		t.Errorf("GetTimeCreatedAndConnected() = %v, %v, %v, want %v, %v", c, conn, err, created, created.Add(time.Hour))
+	}
+	if category, err := network.GetCategory(); err != nil || category != wnlm.NLMNetworkCategoryPrivate {
+		t.Errorf("GetCategory() = %v, %v, want Private", category, err)
+	}
+	if domain, err := network.GetDomainType(); err != nil || domain != wnlm.NLMDomainTypeNonDomainNetwork {
+		t.Errorf("GetDomainType() = %v, %v, want NonDomainNetwork", domain, err)
+	}
+	wantConnectivity := wnlm.NLMConnectivityIPv4Internet | wnlm.NLMConnectivityIPv6LocalNetwork
+	if connectivity, err := network.GetConnectivity(); err != nil || connectivity != wantConnectivity {
+		t.Errorf("GetConnectivity() = %v, %v, want %v", connectivity, err, wantConnectivity)
+	}
+	if connected, err := network.IsConnectedToInternet(); err != nil || !connected {
+		t.Errorf("IsConnectedToInternet() = %t, %v, want true", connected, err)
+	}
+
+	conns, err := network.GetNetworkConnections()
+	if err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+	defer conns.Release()
+	var ids []wnlm.GUID
+	conns.ForEach(func(_ int, conn wnlm.INetworkConnection) bool {
+		id, _ := conn.GetConnectionId()
+		ids = append(ids, id)
+		return true
+	})
+	if !reflect.DeepEqual(ids, []wnlm.GUID{fake.SampleWiFiID}) {
+		t.Errorf("GetNetworkConnections() = %v, want [%v]", ids, fake.SampleWiFiID)
+	}
+}
+
+func TestClientNetworkConnection(t *testing.T) {
+	c, m := newTestClient(t)
+	conn, err := c.NetworkConnection(fake.SampleEthernetID)
+	if err != nil {
+		t.Fatalf("NetworkConnection failed: %v", err)
+	}
+	defer conn.Release()
+
+	if id, err := conn.GetAdapterId(); err != nil || id != fake.SampleAdapterID {
+		t.Errorf("GetAdapterId() = %v, %v, want %v", id, err, fake.SampleAdapterID)
+	}
+	if connected, err := conn.IsConnected(); err != nil || connected {
+		t.Errorf("IsConnected() = %t, %v, want false", connected, err)
+	}
+
+	// the network is fetched again, with its current state
+	m.UpdateNetwork(fake.SampleOfficeID, func(n *fake.Network) { n.Name = "HQ" })
+	network, err := conn.GetNetwork()
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	defer network.Release()
+	if name, err := network.GetName(); err != nil || name != "HQ" {
+		t.Errorf("GetName() = %q, %v, want HQ", name, err)
+	}
+
+	m.RemoveNetwork(fake.SampleOfficeID)
+	if _, err := conn.GetNetwork(); !errors.Is(err, hresult.E_NOT_FOUND) {
+		t.Errorf("GetNetwork() of a removed network = %v, want E_NOT_FOUND", err)
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	c, _ := newTestClient(t)
+	unknown := wnlm.MustParseGUID("{99999999-9999-9999-9999-999999999999}")
+
+	_, err := c.Network(unknown)
+	var apiErr *httpapi.Error
+	if !errors.Is(err, hresult.E_NOT_FOUND) || !errors.As(err, &apiErr) {
+		t.Errorf("Network() of an unknown network = %v, want an *httpapi.Error matching E_NOT_FOUND", err)
+	}
+	if _, err := c.NetworkConnection(unknown); !errors.Is(err, hresult.E_NOT_FOUND) {
+		t.Errorf("NetworkConnection() of an unknown connection = %v, want E_NOT_FOUND", err)
+	}
+}
+
+func TestClientConnectivity(t *testing.T) {
+	c, _ := newTestClient(t)
+	connectivity, err := c.Connectivity()
+	if err != nil {
+		t.Fatalf("Connectivity failed: %v", err)
+	}
+	want := httpapi.Connectivity{
+		Connectivity:        wnlm.NLMConnectivityIPv4Internet | wnlm.NLMConnectivityIPv6LocalNetwork,
+		IPv4:                wnlm.ConnectivityLevelInternet,
+		IPv6:                wnlm.ConnectivityLevelLocalNetwork,
+		Connected:           true,
+		ConnectedToInternet: true,
+	}
+	if connectivity != want {
+		t.Errorf("Connectivity() = %+v, want %+v", connectivity, want)
+	}
+}
+
+func TestClientReadOnly(t *testing.T) {
+	c, m := newTestClient(t)
+	network, err := c.Network(fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("Network failed: %v", err)
+	}
+	defer network.Release()
+
+	for name, set := range map[string]func() error{
+		"SetName":        func() error { return network.SetName("Hacked") },
+		"SetDescription": func() error { return network.SetDescription("Hacked") },
+		"SetCategory":    func() error { return network.SetCategory(wnlm.NLMNetworkCategoryPublic) },
+	} {
+		if err := set(); !errors.Is(err, wnlm.ErrReadOnly) {
+			t.Errorf("%s() = %v, want ErrReadOnly", name, err)
+		}
+	}
+	if got, _ := m.Network(fake.SampleHomeID); got.Name != "Home" || got.Category != wnlm.NLMNetworkCategoryPrivate {
+		t.Errorf("network = %+v, want it unchanged", got)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/connections":
+			w.Write([]byte(`[{"id":`))
+		default:
+			http.Error(w, "oops", http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	c, err := client.New(server.URL + "/api")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := c.Network(fake.SampleHomeID); err == nil || !strings.Contains(err.Error(), "502 Bad Gateway") {
+		t.Errorf("Network() = %v, want the status of the response", err)
+	}
+	if _, err := c.GetNetworkConnections(); err == nil || !strings.Contains(err.Error(), "failed to decode /api/connections") {
+		t.Errorf("GetNetworkConnections() = %v, want a decoding error", err)
+	}
+
+	for _, baseURL := range []string{"host:8080", "ftp://host", "http://host:port"} {
+		if _, err := client.New(baseURL); err == nil {
+			t.Errorf("New(%q) succeeded, want an error", baseURL)
+		}
+	}
+}
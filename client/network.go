@@ -0,0 +1,83 @@
+package client
+
+import (
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// network is a wnlm.INetwork holding the state of a network served by the HTTP API.
+type network struct {
+	client *Client
+	info   wnlm.NetworkInfo
+}
+
+var _ wnlm.INetwork = (*network)(nil)
+
+// GetName returns the name of the network.
+func (n *network) GetName() (string, error) {
+	return n.info.Name, nil
+}
+
+// SetName fails with a *wnlm.ReadOnlyError.
+func (n *network) SetName(string) error {
+	return &wnlm.ReadOnlyError{Interface: "INetwork", Method: "SetName"}
+}
+
+// GetDescription returns the description of the network.
+func (n *network) GetDescription() (string, error) {
+	return n.info.Description, nil
+}
+
+// SetDescription fails with a *wnlm.ReadOnlyError.
+func (n *network) SetDescription(string) error {
+	return &wnlm.ReadOnlyError{Interface: "INetwork", Method: "SetDescription"}
+}
+
+// GetNetworkId returns the GUID of the network.
+func (n *network) GetNetworkId() (wnlm.GUID, error) {
+	return n.info.ID, nil
+}
+
+// GetDomainType returns the domain type of the network.
+func (n *network) GetDomainType() (wnlm.NLMDomainType, error) {
+	return n.info.DomainType, nil
+}
+
+// GetNetworkConnections returns the current network connections of the network.
+func (n *network) GetNetworkConnections() (wnlm.IEnumNetworkConnections, error) {
+	return n.client.networkConnections(func(info wnlm.ConnectionInfo) bool { return info.NetworkID == n.info.ID })
+}
+
+// GetTimeCreatedAndConnected returns the times the network was created and last connected.
+func (n *network) GetTimeCreatedAndConnected() (time.Time, time.Time, error) {
+	return n.info.Created, n.info.LastConnected, nil
+}
+
+// IsConnectedToInternet returns true if the network is connected to the Internet.
+func (n *network) IsConnectedToInternet() (bool, error) {
+	return n.info.ConnectedToInternet, nil
+}
+
+// IsConnected returns true if the network is connected.
+func (n *network) IsConnected() (bool, error) {
+	return n.info.Connected, nil
+}
+
+// GetConnectivity returns the connectivity of the network.
+func (n *network) GetConnectivity() (wnlm.NLMConnectivity, error) {
+	return n.info.Connectivity, nil
+}
+
+// GetCategory returns the category of the network.
+func (n *network) GetCategory() (wnlm.NLMNetworkCategory, error) {
+	return n.info.Category, nil
+}
+
+// SetCategory fails with a *wnlm.ReadOnlyError.
+func (n *network) SetCategory(wnlm.NLMNetworkCategory) error {
+	return &wnlm.ReadOnlyError{Interface: "INetwork", Method: "SetCategory"}
+}
+
+// Release releases the network, which is a no-op.
+func (n *network) Release() {}
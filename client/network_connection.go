@@ -0,0 +1,50 @@
+package client
+
+import "github.com/adrianosela/wnlm"
+
+// networkConnection is a wnlm.INetworkConnection holding the state of a network connection
+// served by the HTTP API.
+type networkConnection struct {
+	client *Client
+	info   wnlm.ConnectionInfo
+}
+
+var _ wnlm.INetworkConnection = (*networkConnection)(nil)
+
+// GetNetwork returns the current state of the network of the network connection.
+func (nc *networkConnection) GetNetwork() (wnlm.INetwork, error) {
+	return nc.client.Network(nc.info.NetworkID)
+}
+
+// IsConnectedToInternet returns true if the network connection is connected to the Internet.
+func (nc *networkConnection) IsConnectedToInternet() (bool, error) {
+	return nc.info.ConnectedToInternet, nil
+}
+
+// IsConnected returns true if the network connection is connected.
+func (nc *networkConnection) IsConnected() (bool, error) {
+	return nc.info.Connected, nil
+}
+
+// GetConnectivity returns the connectivity of the network connection.
+func (nc *networkConnection) GetConnectivity() (wnlm.NLMConnectivity, error) {
+	return nc.info.Connectivity, nil
+}
+
+// GetConnectionId returns the GUID of the network connection.
+func (nc *networkConnection) GetConnectionId() (wnlm.GUID, error) {
+	return nc.info.ID, nil
+}
+
+// GetAdapterId returns the GUID of the network adapter of the network connection.
+func (nc *networkConnection) GetAdapterId() (wnlm.GUID, error) {
+	return nc.info.AdapterID, nil
+}
+
+// GetDomainType returns the domain type of the network connection.
+func (nc *networkConnection) GetDomainType() (wnlm.NLMDomainType, error) {
+	return nc.info.DomainType, nil
+}
+
+// Release releases the network connection, which is a no-op.
+func (nc *networkConnection) Release() {}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// runList runs the list command, which outputs every network or network connection.
+func runList(a *app, args []string) error {
+	fs := a.newFlagSet("list")
+	opts := addOutputFlags(fs, true)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return a.usageError(fs, "expected networks or connections")
+	}
+	if err := opts.validate(); err != nil {
+		return a.usageError(fs, "%v", err)
+	}
+
+	switch positional[0] {
+	case "networks", "network", "net":
+		fields, err := selectFields(opts, networkFields, defaultNetworkFields)
+		if err != nil {
+			return a.usageError(fs, "%v", err)
+		}
+		snapshot, err := a.snapshot()
+		if err != nil {
+			return err
+		}
+		if err := sortItems(opts, snapshot.Networks, networkFields); err != nil {
+			return a.usageError(fs, "%v", err)
+		}
+		return writeItems(a.stdout, opts, snapshot.Networks, fields)
+	case "connections", "connection", "conn":
+		fields, err := selectFields(opts, connectionFields, defaultConnectionFields)
+		if err != nil {
+			return a.usageError(fs, "%v", err)
+		}
+		snapshot, err := a.snapshot()
+		if err != nil {
+			return err
+		}
+		if err := sortItems(opts, snapshot.Connections, connectionFields); err != nil {
+			return a.usageError(fs, "%v", err)
+		}
+		return writeItems(a.stdout, opts, snapshot.Connections, fields)
+	default:
+		return a.usageError(fs, "unknown list %q, expected networks or connections", positional[0])
+	}
+}
+
+// runShow runs the show command, which outputs every field of a network or network connection.
+func runShow(a *app, args []string) error {
+	fs := a.newFlagSet("show")
+	opts := addOutputFlags(fs, false)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return a.usageError(fs, "expected the GUID of a network or network connection")
+	}
+	if err := opts.validate(); err != nil {
+		return a.usageError(fs, "%v", err)
+	}
+	id, err := wnlm.ParseGUID(positional[0])
+	if err != nil {
+		return a.usageError(fs, "%v", err)
+	}
+
+	snapshot, err := a.snapshot()
+	if err != nil {
+		return err
+	}
+	if network, ok := snapshot.Network(id); ok {
+		fields, err := selectFields(opts, networkFields, nil)
+		if err != nil {
+			return a.usageError(fs, "%v", err)
+		}
+		if len(fields) == 0 {
+			fields = networkFields
+		}
+		return writeItem(a.stdout, opts, network, fields)
+	}
+	if conn, ok := snapshot.Connection(id); ok {
+		fields, err := selectFields(opts, connectionFields, nil)
+		if err != nil {
+			return a.usageError(fs, "%v", err)
+		}
+		if len(fields) == 0 {
+			fields = connectionFields
+		}
+		return writeItem(a.stdout, opts, conn, fields)
+	}
+	return fmt.Errorf("no network or network connection with GUID %s", id)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adrianosela/wnlm/fake"
+)
+
+func TestListAndShow(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "networks table",
+			args: []string{"list", "networks"},
+			want: `ID                                      NAME    CATEGORY  CONNECTIVITY
+{11111111-1111-1111-1111-111111111111}  Home    Private   IPv4Internet, IPv6LocalNetwork
+{22222222-2222-2222-2222-222222222222}  Office  Public    Disconnected
+`,
+		},
+		{
+			name: "sorted connections",
+			args: []string{"list", "connections", "--sort", "connectivity", "--fields", "id,connectivity"},
+			want: `ID                                      CONNECTIVITY
+{BBBBBBBB-BBBB-BBBB-BBBB-BBBBBBBBBBBB}  Disconnected
+{AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA}  IPv4Internet, IPv6LocalNetwork
+`,
+		},
+		{
+			name: "networks json",
+			args: []string{"list", "networks", "-o", "json", "--fields", "id,name"},
+			want: `[
+  {
+    "id": "{11111111-1111-1111-1111-111111111111}",
+    "name": "Home"
+  },
+  {
+    "id": "{22222222-2222-2222-2222-222222222222}",
+    "name": "Office"
+  }
+]
+`,
+		},
+		{
+			name: "networks ndjson",
+			args: []string{"list", "net", "-o", "ndjson", "--fields", "name,connectivity"},
+			want: `{"name":"Home","connectivity":"IPv4Internet, IPv6LocalNetwork"}
+{"name":"Office","connectivity":"Disconnected"}
+`,
+		},
+		{
+			name: "networks template",
+			args: []string{"list", "networks", "--format", "{{.Name}}: {{.Connectivity}}"},
+			want: "Home: IPv4Internet, IPv6LocalNetwork\nOffice: Disconnected\n",
+		},
+		{
+			name: "show network",
+			args: []string{"show", fake.SampleHomeID.String()},
+			want: `id:                   {11111111-1111-1111-1111-111111111111}
+name:                 Home
+description:          Home network
+category:             Private
+domainType:           None
+connectivity:         IPv4Internet, IPv6LocalNetwork
+connected:            true
+connectedToInternet:  true
+created:              2024-01-02T03:04:05Z
+lastConnected:        2024-01-02T04:04:05Z
+connections:          {AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA}
+`,
+		},
+		{
+			name: "show connection json",
+			args: []string{"show", fake.SampleWiFiID.String(), "-o", "json"},
+			want: `{
+  "id": "{AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA}",
+  "adapterId": "{CCCCCCCC-CCCC-CCCC-CCCC-CCCCCCCCCCCC}",
+  "networkId": "{11111111-1111-1111-1111-111111111111}",
+  "domainType": "None",
+  "connectivity": "IPv4Internet, IPv6LocalNetwork",
+  "connected": true,
+  "connectedToInternet": true
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := newTestApp(t)
+			a.mustRun(t, exitOK, test.args...)
+			if got := a.stdout.String(); got != test.want {
+				t.Errorf("wnlm %s output:\n%s\nwant:\n%s", strings.Join(test.args, " "), got, test.want)
+			}
+		})
+	}
+}
+
+func TestListAndShowErrors(t *testing.T) {
+	tests := []struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		{[]string{"list"}, exitUsage, "expected networks or connections"},
+		{[]string{"list", "routers"}, exitUsage, `unknown list "routers"`},
+		{[]string{"list", "networks", "--fields", "ssid"}, exitUsage, "ssid"},
+		{[]string{"show", "home"}, exitUsage, "home"},
+		{[]string{"show", "{12345678-1111-1111-1111-111111111111}"}, exitError, "no network or network connection with GUID {12345678-1111-1111-1111-111111111111}"},
+	}
+	for _, test := range tests {
+		a := newTestApp(t)
+		a.mustRun(t, test.code, test.args...)
+		if !strings.Contains(a.stderr.String(), test.stderr) {
+			t.Errorf("wnlm %s stderr = %q, want it to contain %q", strings.Join(test.args, " "), a.stderr, test.stderr)
+		}
+		if a.stdout.Len() != 0 {
+			t.Errorf("wnlm %s output %q, want none", strings.Join(test.args, " "), a.stdout)
+		}
+	}
+}
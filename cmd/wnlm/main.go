@@ -0,0 +1,278 @@
+// Command wnlm inspects and manages the networks known to the Windows Network List Manager.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/client"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+const (
+	// exitOK is the exit code of successful commands.
+	exitOK = 0
+	// exitError is the exit code of failed commands.
+	exitError = 1
+	// exitUsage is the exit code of commands used incorrectly.
+	exitUsage = 2
+)
+
+// errUsage is returned by commands used incorrectly, after printing their usage.
+var errUsage = errors.New("invalid usage")
+
+// command is a wnlm subcommand.
+type command struct {
+	usage string
+	run   func(a *app, args []string) error
+}
+
+// commands are the wnlm subcommands, by name.
+var commands map[string]command
+
+func init() {
+	// commands are registered in init since their flag sets refer back to commands for their usage.
+	commands = map[string]command{
+		"list":  {usage: "list networks|connections [flags]", run: runList},
+		"serve": {usage: "serve [--addr <address>] [--allow-writes] (GET /networks, /networks/{guid}, /connections, /connectivity, /events, and PUT /networks/{guid} with --allow-writes, unauthenticated)", run: runServe},
+		"set":   {usage: "set <guid|name|glob> [--name <name>] [--description <description>] [--category public|private] [--dry-run] [--yes] (only networks with an active connection)", run: runSet},
+		"show":  {usage: "show <guid> [flags]", run: runShow},
+		"top":   {usage: "top [--interval <duration>] [--no-color] (q quits, / filters, enter shows details)", run: runTop},
+		"tree":  {usage: "tree", run: runTree},
+		"wait":  {usage: "wait --for ipv4-internet|ipv6-internet|any-internet|connected|network=<name> [--timeout <duration>] (exits 0 when met, 3 on timeout, 1 on errors)", run: runWait},
+		"watch": {usage: "watch [--network <name|guid>] [--once] [flags]", run: runWatch},
+	}
+}
+
+// app holds what commands need from their environment.
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// newManager returns the INetworkListManager used by commands, and a function to release it.
+	newManager func() (wnlm.INetworkListManager, func(), error)
+}
+
+func main() {
+	a := &app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, newManager: newSessionManager}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+// run runs the command line given by args and returns its exit code.
+func (a *app) run(args []string) int {
+	fs := flag.NewFlagSet("wnlm", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fakeState := fs.String("fake", "", "use an in-memory fake manager loaded from the given JSON `file` (a fake.Fixture)")
+	remote := fs.String("remote", "", "inspect the networks of a remote machine through the HTTP API of wnlm serve at the given `URL`")
+	fs.Usage = func() { a.usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		a.usage(fs)
+		return exitUsage
+	}
+	if *fakeState != "" && *remote != "" {
+		fmt.Fprintf(a.stderr, "wnlm: --fake and --remote are mutually exclusive\n")
+		return exitUsage
+	}
+	if *fakeState != "" {
+		a.newManager = func() (wnlm.INetworkListManager, func(), error) {
+			return newFakeManager(*fakeState)
+		}
+	}
+	if *remote != "" {
+		a.newManager = func() (wnlm.INetworkListManager, func(), error) {
+			return newRemoteManager(*remote)
+		}
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(a.stderr, "wnlm: unknown command %q\n", fs.Arg(0))
+		a.usage(fs)
+		return exitUsage
+	}
+	return a.exitCode(cmd.run(a, fs.Args()[1:]))
+}
+
+// exitCode prints the error returned by a command, if any, and returns the matching exit code.
+func (a *app) exitCode(err error) int {
+	var exit *exitCodeError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return exitUsage
+	case errors.As(err, &exit):
+		if exit.err != nil {
+			fmt.Fprintf(a.stderr, "wnlm: %v\n", exit.err)
+		}
+		return exit.code
+	default:
+		fmt.Fprintf(a.stderr, "wnlm: %v\n", err)
+		return exitError
+	}
+}
+
+// usage prints the usage of wnlm.
+func (a *app) usage(fs *flag.FlagSet) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(a.stderr, "usage: wnlm [flags] <command> [args]\n\ncommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintf(a.stderr, "\nflags:\n")
+	fs.PrintDefaults()
+}
+
+// exitCodeError is an error with a specific exit code.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+// Error implements the error interface.
+func (e *exitCodeError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit code %d", e.code)
+	}
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// newFlagSet returns a flag.FlagSet for a command, printing its usage to stderr.
+func (a *app) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("wnlm "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: wnlm %s\n", commands[name].usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags of a command, which may be interspersed with (and
+// follow) its positional arguments, and returns its positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// usageError prints a usage error for a command and returns errUsage.
+func (a *app) usageError(fs *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(a.stderr, "%s: %s\n", fs.Name(), fmt.Sprintf(format, args...))
+	fs.Usage()
+	return errUsage
+}
+
+// withManager runs fn with a new INetworkListManager, releasing it afterwards.
+func (a *app) withManager(fn func(wnlm.INetworkListManager) error) error {
+	nlm, release, err := a.newManager()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn(nlm)
+}
+
+// snapshot returns a snapshot of every network and network connection.
+func (a *app) snapshot() (*wnlm.Snapshot, error) {
+	var snapshot *wnlm.Snapshot
+	err := a.withManager(func(nlm wnlm.INetworkListManager) (err error) {
+		snapshot, err = wnlm.TakeSnapshot(nlm)
+		return err
+	})
+	return snapshot, err
+}
+
+// newSessionManager returns an INetworkListManager from a new Session.
+func newSessionManager() (wnlm.INetworkListManager, func(), error) {
+	session, err := wnlm.NewSession()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize session: %w", err)
+	}
+	nlm, err := session.NewNetworkListManager()
+	if err != nil {
+		session.Close()
+		return nil, nil, fmt.Errorf("failed to initialize network list manager: %w", err)
+	}
+	return nlm, func() { session.Close() }, nil
+}
+
+// newFakeManager returns a fake INetworkListManager loaded from a fake.Fixture file,
+// which plays the script of the fixture until it is released.
+func newFakeManager(path string) (wnlm.INetworkListManager, func(), error) {
+	fixture, err := fake.ReadFixture(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	nlm := fake.New()
+	nlm.SetState(fixture.State)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = nlm.Play(ctx, fixture.Script)
+	}()
+	return nlm, func() {
+		cancel()
+		<-done
+		nlm.Release()
+	}, nil
+}
+
+// newRemoteManager returns an INetworkListManager inspecting the networks of a remote
+// machine through the HTTP API served by wnlm serve at the given URL.
+func newRemoteManager(baseURL string) (wnlm.INetworkListManager, func(), error) {
+	nlm, err := client.New(baseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nlm, nlm.Release, nil
+}
+
+// splitList splits a comma separated list, ignoring empty elements.
+func splitList(s string) []string {
+	list := []string{}
+	for _, element := range strings.Split(s, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			list = append(list, element)
+		}
+	}
+	return list
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+// testApp is an app using a fake manager, with its output captured.
+type testApp struct {
+	*app
+	fake   *fake.NetworkListManager
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+}
+
+// newTestApp returns a testApp whose fake manager holds fake.SampleState.
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	m := fake.NewSample()
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	a := &app{
+		stdin:  strings.NewReader(""),
+		stdout: stdout,
+		stderr: stderr,
+		newManager: func() (wnlm.INetworkListManager, func(), error) {
+			return m, func() {}, nil
+		},
+	}
+	return &testApp{app: a, fake: m, stdout: stdout, stderr: stderr}
+}
+
+// mustRun runs a command line, failing the test unless it exits with the given code.
+func (a *testApp) mustRun(t *testing.T, code int, args ...string) {
+	t.Helper()
+	if got := a.run(args); got != code {
+		t.Fatalf("wnlm %s exited with %d, want %d; stderr:\n%s", strings.Join(args, " "), got, code, a.stderr)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	a := newTestApp(t)
+	a.mustRun(t, exitUsage, "frobnicate")
+	if !strings.Contains(a.stderr.String(), `unknown command "frobnicate"`) {
+		t.Errorf("stderr = %q, want the unknown command", a.stderr)
+	}
+}
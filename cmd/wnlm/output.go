@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+const (
+	// formatTable is the output format of aligned columns.
+	formatTable = "table"
+	// formatJSON is the output format of a (pretty-printed) JSON array.
+	formatJSON = "json"
+	// formatNDJSON is the output format of one JSON object per line.
+	formatNDJSON = "ndjson"
+)
+
+// field is an output field of items of type T.
+type field[T any] struct {
+	name  string
+	value func(T) any
+}
+
+// networkFields are the output fields of networks.
+var networkFields = []field[wnlm.NetworkInfo]{
+	{"id", func(n wnlm.NetworkInfo) any { return n.ID }},
+	{"name", func(n wnlm.NetworkInfo) any { return n.Name }},
+	{"description", func(n wnlm.NetworkInfo) any { return n.Description }},
+	{"category", func(n wnlm.NetworkInfo) any { return n.Category }},
+	{"domainType", func(n wnlm.NetworkInfo) any { return n.DomainType }},
+	{"connectivity", func(n wnlm.NetworkInfo) any { return n.Connectivity }},
+	{"connected", func(n wnlm.NetworkInfo) any { return n.Connected }},
+	{"connectedToInternet", func(n wnlm.NetworkInfo) any { return n.ConnectedToInternet }},
+	{"created", func(n wnlm.NetworkInfo) any { return n.Created }},
+	{"lastConnected", func(n wnlm.NetworkInfo) any { return n.LastConnected }},
+	{"connections", func(n wnlm.NetworkInfo) any { return n.Connections }},
+}
+
+// defaultNetworkFields are the fields of networks output in table format, unless chosen otherwise.
+var defaultNetworkFields = []string{"id", "name", "category", "connectivity"}
+
+// connectionFields are the output fields of network connections.
+var connectionFields = []field[wnlm.ConnectionInfo]{
+	{"id", func(c wnlm.ConnectionInfo) any { return c.ID }},
+	{"adapterId", func(c wnlm.ConnectionInfo) any { return c.AdapterID }},
+	{"networkId", func(c wnlm.ConnectionInfo) any { return c.NetworkID }},
+	{"domainType", func(c wnlm.ConnectionInfo) any { return c.DomainType }},
+	{"connectivity", func(c wnlm.ConnectionInfo) any { return c.Connectivity }},
+	{"connected", func(c wnlm.ConnectionInfo) any { return c.Connected }},
+	{"connectedToInternet", func(c wnlm.ConnectionInfo) any { return c.ConnectedToInternet }},
+}
+
+// defaultConnectionFields are the fields of network connections output in table format, unless chosen otherwise.
+var defaultConnectionFields = []string{"id", "adapterId", "networkId", "connectivity"}
+
+// outputOptions are the options of commands which output networks or network connections.
+type outputOptions struct {
+	format   string
+	fields   string
+	sort     string
+	template string
+
+	tmpl *template.Template
+}
+
+// addOutputFlags adds the flags setting outputOptions to a command's flag.FlagSet.
+func addOutputFlags(fs *flag.FlagSet, sortable bool) *outputOptions {
+	opts := &outputOptions{}
+	fs.StringVar(&opts.format, "o", formatTable, "output `format`: table, json or ndjson")
+	fs.StringVar(&opts.fields, "fields", "", "comma separated `list` of fields to output (default: all, or a few for tables)")
+	fs.StringVar(&opts.template, "format", "", "Go `template` to output each item with, overriding -o, e.g. '{{.Name}} {{.Connectivity}}'\n"+
+		"(see wnlm.NetworkInfo and wnlm.ConnectionInfo for fields, and the functions hasFlag, flags, join, since, duration, json, upper and lower)")
+	if sortable {
+		fs.StringVar(&opts.sort, "sort", "", "`field` to sort by, descending if prefixed with '-'")
+	}
+	return opts
+}
+
+// validate checks the output options.
+func (o *outputOptions) validate() error {
+	if o.template != "" {
+		tmpl, err := parseTemplate(o.template)
+		if err != nil {
+			return fmt.Errorf("invalid format template: %w", err)
+		}
+		o.tmpl = tmpl
+		return nil
+	}
+	switch o.format {
+	case formatTable, formatJSON, formatNDJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", o.format)
+	}
+}
+
+// selectFields returns the fields chosen by the output options, in the order given.
+func selectFields[T any](opts *outputOptions, fields []field[T], tableDefaults []string) ([]field[T], error) {
+	names := splitList(opts.fields)
+	if len(names) == 0 {
+		if opts.format != formatTable {
+			return fields, nil
+		}
+		names = tableDefaults
+	}
+	selected := make([]field[T], 0, len(names))
+	for _, name := range names {
+		f, ok := findField(fields, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q (fields: %s)", name, fieldNames(fields))
+		}
+		selected = append(selected, f)
+	}
+	return selected, nil
+}
+
+// findField returns the field with the given name, ignoring case.
+func findField[T any](fields []field[T], name string) (field[T], bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return field[T]{}, false
+}
+
+// fieldNames returns the comma separated names of fields.
+func fieldNames[T any](fields []field[T]) string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// sortItems sorts items by the field named by the output options, if any.
+func sortItems[T any](opts *outputOptions, items []T, fields []field[T]) error {
+	if opts.sort == "" {
+		return nil
+	}
+	name, descending := strings.CutPrefix(opts.sort, "-")
+	f, ok := findField(fields, name)
+	if !ok {
+		return fmt.Errorf("unknown sort field %q (fields: %s)", name, fieldNames(fields))
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		cmp := compareValues(f.value(items[i]), f.value(items[j]))
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	return nil
+}
+
+// compareValues compares two values of the same field.
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case wnlm.GUID:
+		return a.Compare(b.(wnlm.GUID))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case bool:
+		return compareOrdered(boolToInt(a), boolToInt(b.(bool)))
+	case []wnlm.GUID:
+		return compareOrdered(len(a), len(b.([]wnlm.GUID)))
+	case wnlm.NLMConnectivity:
+		return compareOrdered(a, b.(wnlm.NLMConnectivity))
+	default:
+		return strings.Compare(strings.ToLower(formatValue(a)), strings.ToLower(formatValue(b)))
+	}
+}
+
+// compareOrdered compares two ordered values.
+func compareOrdered[T int | wnlm.NLMConnectivity](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// boolToInt returns 1 for true and 0 for false.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// formatValue returns the human readable form of a field value.
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.Local().Format(time.RFC3339)
+	case []wnlm.GUID:
+		ids := make([]string, len(v))
+		for i, id := range v {
+			ids[i] = id.String()
+		}
+		return strings.Join(ids, ",")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// writeItems writes items in the chosen output format.
+func writeItems[T any](w io.Writer, opts *outputOptions, items []T, fields []field[T]) error {
+	if opts.tmpl != nil {
+		return writeTemplate(w, opts.tmpl, items)
+	}
+	switch opts.format {
+	case formatJSON:
+		objects := make([]json.RawMessage, len(items))
+		for i, item := range items {
+			object, err := marshalObject(item, fields)
+			if err != nil {
+				return err
+			}
+			objects[i] = object
+		}
+		return writeJSON(w, objects)
+	case formatNDJSON:
+		for _, item := range items {
+			object, err := marshalObject(item, fields)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "%s\n", object); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		headers := make([]string, len(fields))
+		for i, f := range fields {
+			headers[i] = strings.ToUpper(f.name)
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+		for _, item := range items {
+			values := make([]string, len(fields))
+			for i, f := range fields {
+				values[i] = formatValue(f.value(item))
+			}
+			fmt.Fprintln(tw, strings.Join(values, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// writeItem writes a single item in the chosen output format, as one
+// line per field (rather than a single row) in table format.
+func writeItem[T any](w io.Writer, opts *outputOptions, item T, fields []field[T]) error {
+	if opts.tmpl != nil {
+		return writeTemplate(w, opts.tmpl, []T{item})
+	}
+	switch opts.format {
+	case formatJSON:
+		object, err := marshalObject(item, fields)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, object)
+	case formatNDJSON:
+		return writeItems(w, opts, []T{item}, fields)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, f := range fields {
+			fmt.Fprintf(tw, "%s:\t%s\n", f.name, formatValue(f.value(item)))
+		}
+		return tw.Flush()
+	}
+}
+
+// marshalObject returns the JSON object of an item with the given fields, in order.
+func marshalObject[T any](item T, fields []field[T]) (json.RawMessage, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value(item))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field %s: %w", f.name, err)
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return json.RawMessage(b.String()), nil
+}
+
+// writeJSON writes a value as indented JSON.
+func writeJSON(w io.Writer, value any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/httpapi"
+)
+
+// runServe runs the serve command, which serves the state of networks as a JSON API over HTTP.
+func runServe(a *app, args []string) error {
+	fs := a.newFlagSet("serve")
+	addr := fs.String("addr", "localhost:8080", "`address` to listen on, e.g. :8080 to listen on every interface")
+	allowWrites := fs.Bool("allow-writes", false, "serve PUT /networks/{guid} requests changing networks (rejected with 403 Forbidden by default), which are not authenticated: only expose them behind something that authenticates requests")
+	interval := fs.Duration("interval", time.Second, "`interval` at which to check for changes streamed at /events")
+	allowedHosts := fs.String("allowed-hosts", "", "comma separated host `names` requests may be addressed to, besides the host of --addr, the name of this machine, localhost and IP addresses")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return a.usageError(fs, "unexpected arguments %q", positional)
+	}
+	if *interval <= 0 {
+		return a.usageError(fs, "interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return a.withManager(func(nlm wnlm.INetworkListManager) error {
+		listener, err := net.Listen("tcp", *addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", *addr, err)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		watcher := wnlm.NewWatcher(nlm, *interval, wnlm.WithPollErrorHandler(func(err error) {
+			fmt.Fprintf(a.stderr, "wnlm: failed to poll for changes: %v\n", err)
+		}))
+		events := httpapi.NewEventStream(watcher)
+		watched := make(chan struct{})
+		go func() {
+			defer close(watched)
+			watcher.Run(ctx)
+		}()
+		// the event stream is closed as soon as ctx is done, since the server waits for its streams to end when shutting down.
+		go func() {
+			<-ctx.Done()
+			events.Close()
+		}()
+		defer func() {
+			cancel()
+			<-watched
+		}()
+
+		fmt.Fprintf(a.stderr, "wnlm: serving on http://%s\n", listener.Addr())
+		if *allowWrites && !isLoopback(listener.Addr()) {
+			fmt.Fprintf(a.stderr, "wnlm: anyone who can reach %s can change networks, only expose it behind something that authenticates requests\n", listener.Addr())
+		}
+		return serve(ctx, listener, checkHost(hostNames(*addr, *allowedHosts), apiHandler(nlm, events, *allowWrites)))
+	})
+}
+
+// apiHandler returns the http.Handler serving the API for nlm, and events at /events.
+// Requests changing networks are rejected unless allowWrites is set.
+func apiHandler(nlm wnlm.INetworkListManager, events http.Handler, allowWrites bool) http.Handler {
+	if !allowWrites {
+		nlm = wnlm.NewReadOnlyNetworkListManager(nlm)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", httpapi.NewHandler(nlm))
+	mux.Handle("GET /events", events)
+	return mux
+}
+
+// hostNames returns the host names requests to the serve command may be addressed to: the
+// host of addr (the address listened on), the name of this machine, and those in the
+// comma separated list given with --allowed-hosts.
+func hostNames(addr string, allowedHosts string) []string {
+	var names []string
+	if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+		names = append(names, host)
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		names = append(names, hostname)
+	}
+	for _, name := range strings.Split(allowedHosts, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkHost returns an http.Handler rejecting requests whose Host header is neither one of
+// the given names, localhost nor an IP address, such that web pages cannot make requests
+// to the API by having their own domain name resolve to the address listened on (DNS
+// rebinding).
+func checkHost(names []string, h http.Handler) http.Handler {
+	allowed := func(host string) bool {
+		if strings.EqualFold(host, "localhost") || net.ParseIP(host) != nil {
+			return true
+		}
+		for _, name := range names {
+			if strings.EqualFold(host, name) {
+				return true
+			}
+		}
+		return false
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = strings.TrimSuffix(strings.TrimPrefix(r.Host, "["), "]")
+		}
+		if !allowed(host) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			_ = json.NewEncoder(w).Encode(&httpapi.Error{Message: fmt.Sprintf("unexpected host %q", r.Host)})
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// isLoopback returns true if addr is a TCP address on a loopback interface.
+func isLoopback(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
+
+// serve serves HTTP requests with handler on listener until ctx is done, then shuts down gracefully.
+func serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() { errs <- server.Serve(listener) }()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrianosela/wnlm/fake"
+)
+
+func TestAPIHandler(t *testing.T) {
+	events := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	tests := []struct {
+		allowWrites bool
+		status      int
+		name        string
+	}{
+		{false, http.StatusForbidden, "Home"},
+		{true, http.StatusOK, "House"},
+	}
+	for _, test := range tests {
+		a := newTestApp(t)
+		h := apiHandler(a.fake, events, test.allowWrites)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("PUT", "/networks/"+fake.SampleHomeID.String(), strings.NewReader(`{"name":"House"}`)))
+		if rec.Code != test.status {
+			t.Errorf("allowWrites=%t: PUT = %d, want %d", test.allowWrites, rec.Code, test.status)
+		}
+		if network, _ := a.fake.Network(fake.SampleHomeID); network.Name != test.name {
+			t.Errorf("allowWrites=%t: network name = %q, want %q", test.allowWrites, network.Name, test.name)
+		}
+
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/events", nil))
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("GET /events = %d, want it served by the event stream", rec.Code)
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	h := checkHost([]string{"host.corp"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	tests := []struct {
+		host   string
+		status int
+	}{
+		{"host.corp:8080", http.StatusOK},
+		{"HOST.corp", http.StatusOK},
+		{"localhost:8080", http.StatusOK},
+		{"127.0.0.1:8080", http.StatusOK},
+		{"[::1]:8080", http.StatusOK},
+		{"[::1]", http.StatusOK},
+		{"attacker.example:8080", http.StatusMisdirectedRequest},
+		{"localhost.attacker.example", http.StatusMisdirectedRequest},
+		{"host.corp.attacker.example", http.StatusMisdirectedRequest},
+		{"", http.StatusMisdirectedRequest},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/networks", nil)
+		req.Host = test.host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.status {
+			t.Errorf("GET with Host %q = %d, want %d", test.host, rec.Code, test.status)
+		}
+		if test.status != http.StatusOK && !strings.Contains(rec.Body.String(), `"error":"unexpected host`) {
+			t.Errorf("GET with Host %q = %s, want an error", test.host, rec.Body)
+		}
+	}
+}
+
+func TestHostNames(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no host name: %v", err)
+	}
+	tests := []struct {
+		addr, allowedHosts string
+		want               []string
+	}{
+		{"localhost:8080", "", []string{"localhost", hostname}},
+		{":8080", "", []string{hostname}},
+		{":8080", "host.corp, host ,", []string{hostname, "host.corp", "host"}},
+	}
+	for _, test := range tests {
+		if got := hostNames(test.addr, test.allowedHosts); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("hostNames(%q, %q) = %q, want %q", test.addr, test.allowedHosts, got, test.want)
+		}
+	}
+}
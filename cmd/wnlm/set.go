@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// noActiveConnectionHint explains why a network may not be found: networks are only found
+// through their network connections, so those without one cannot be changed.
+const noActiveConnectionHint = "disconnected and remembered networks cannot be changed"
+
+// networkChange is a change to the properties of a network.
+type networkChange struct {
+	name        *string
+	description *string
+	category    *wnlm.NLMNetworkCategory
+}
+
+// runSet runs the set command, which changes the name, description or category of networks.
+func runSet(a *app, args []string) error {
+	fs := a.newFlagSet("set")
+	var change networkChange
+	optionalString(fs, &change.name, "name", "new `name` of the network(s)")
+	optionalString(fs, &change.description, "description", "new `description` of the network(s)")
+	category := fs.String("category", "", "new `category` of the network(s): public or private")
+	dryRun := fs.Bool("dry-run", false, "show the changes without making them")
+	yes := fs.Bool("yes", false, "do not ask for confirmation when changing more than one network")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return a.usageError(fs, "expected the GUID, name or name glob (e.g. \"Corp*\") of the network(s) to change")
+	}
+	if *category != "" {
+		parsed, err := wnlm.ParseNLMNetworkCategory(*category)
+		if err != nil || (parsed != wnlm.NLMNetworkCategoryPublic && parsed != wnlm.NLMNetworkCategoryPrivate) {
+			return a.usageError(fs, "invalid category %q, expected public or private", *category)
+		}
+		change.category = &parsed
+	}
+	if change.name == nil && change.description == nil && change.category == nil {
+		return a.usageError(fs, "nothing to set, expected at least one of --name, --description or --category")
+	}
+
+	return a.withManager(func(nlm wnlm.INetworkListManager) error {
+		snapshot, err := wnlm.TakeSnapshot(nlm)
+		if err != nil {
+			return err
+		}
+		matches, err := selectNetworks(snapshot, positional[0])
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no network with an active connection matches %q (%s)", positional[0], noActiveConnectionHint)
+		}
+
+		prefix := ""
+		if *dryRun {
+			prefix = "(dry run) "
+		}
+		for _, network := range matches {
+			fmt.Fprintf(a.stdout, "%s%s %q: %s\n", prefix, network.ID, network.Name, change.describe(network))
+		}
+		if *dryRun {
+			return nil
+		}
+		if len(matches) > 1 && !*yes {
+			if ok, err := a.confirm(fmt.Sprintf("Change %d networks?", len(matches))); err != nil || !ok {
+				return errors.Join(errors.New("aborted"), err)
+			}
+		}
+		for _, network := range matches {
+			if err := change.apply(nlm, network.ID); err != nil {
+				return fmt.Errorf("failed to change network %s %q: %w", network.ID, network.Name, explainSetError(err))
+			}
+		}
+		return nil
+	})
+}
+
+// optionalString defines a string flag which is only set (to a non-nil pointer) if given.
+func optionalString(fs *flag.FlagSet, value **string, name string, usage string) {
+	fs.Func(name, usage, func(s string) error {
+		*value = &s
+		return nil
+	})
+}
+
+// selectNetworks returns the networks whose GUID, name or name glob (see path.Match) matches a selector.
+func selectNetworks(snapshot *wnlm.Snapshot, selector string) ([]wnlm.NetworkInfo, error) {
+	if id, err := wnlm.ParseGUID(selector); err == nil {
+		if network, ok := snapshot.Network(id); ok {
+			return []wnlm.NetworkInfo{network}, nil
+		}
+		return nil, nil
+	}
+	if _, err := path.Match(selector, ""); err != nil {
+		return nil, fmt.Errorf("invalid network name glob %q: %w", selector, err)
+	}
+	matches := []wnlm.NetworkInfo{}
+	for _, network := range snapshot.Networks {
+		if ok, _ := path.Match(selector, network.Name); ok || network.Name == selector {
+			matches = append(matches, network)
+		}
+	}
+	return matches, nil
+}
+
+// describe returns the human readable form of the change to a network, as "old -> new" values.
+func (c networkChange) describe(network wnlm.NetworkInfo) string {
+	changes := []string{}
+	if c.name != nil {
+		changes = append(changes, fmt.Sprintf("name %q -> %q", network.Name, *c.name))
+	}
+	if c.description != nil {
+		changes = append(changes, fmt.Sprintf("description %q -> %q", network.Description, *c.description))
+	}
+	if c.category != nil {
+		changes = append(changes, fmt.Sprintf("category %s -> %s", network.Category, *c.category))
+	}
+	return strings.Join(changes, ", ")
+}
+
+// apply makes the change to the network with the given GUID.
+func (c networkChange) apply(nlm wnlm.INetworkListManager, id wnlm.GUID) error {
+	network, err := wnlm.FindNetwork(nlm, id)
+	if errors.Is(err, hresult.E_NOT_FOUND) {
+		return fmt.Errorf("network %s has no active connection (%s): %w", id, noActiveConnectionHint, hresult.E_NOT_FOUND)
+	}
+	if err != nil {
+		return err
+	}
+	defer network.Release()
+
+	if c.name != nil {
+		if err := network.SetName(*c.name); err != nil {
+			return fmt.Errorf("failed to set name: %w", err)
+		}
+	}
+	if c.description != nil {
+		if err := network.SetDescription(*c.description); err != nil {
+			return fmt.Errorf("failed to set description: %w", err)
+		}
+	}
+	if c.category != nil {
+		if err := network.SetCategory(*c.category); err != nil {
+			return fmt.Errorf("failed to set category: %w", err)
+		}
+	}
+	return nil
+}
+
+// explainSetError adds a hint to errors caused by missing privileges.
+func explainSetError(err error) error {
+	if errors.Is(err, hresult.E_ACCESSDENIED) || errors.Is(err, hresult.E_ELEVATION_REQUIRED) {
+		return fmt.Errorf("%w (changing networks requires administrator rights, run wnlm from an elevated prompt)", err)
+	}
+	return err
+}
+
+// confirm asks a yes/no question on stderr, reading the answer from stdin.
+func (a *app) confirm(question string) (bool, error) {
+	fmt.Fprintf(a.stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// addCorpNetworks adds two networks named "Corp-*" to the fake manager of a.
+func addCorpNetworks(a *testApp) (first, second wnlm.GUID) {
+	first = wnlm.MustParseGUID("{44444444-4444-4444-4444-444444444444}")
+	second = wnlm.MustParseGUID("{55555555-5555-5555-5555-555555555555}")
+	for i, id := range []wnlm.GUID{first, second} {
+		a.fake.AddNetwork(fake.Network{ID: id, Name: fmt.Sprintf("Corp-%d", i+1), Category: wnlm.NLMNetworkCategoryPublic})
+		a.fake.AddConnection(fake.Connection{ID: wnlm.GUID{Data1: id.Data1, Data2: 0xFFFF}, AdapterID: fake.SampleAdapterID, NetworkID: id})
+	}
+	return first, second
+}
+
+// networkState returns the state of a network of the fake manager of a, failing the test if there is none.
+func networkState(t *testing.T, a *testApp, id wnlm.GUID) fake.Network {
+	t.Helper()
+	network, ok := a.fake.Network(id)
+	if !ok {
+		t.Fatalf("no network %s", id)
+	}
+	return network
+}
+
+func TestSet(t *testing.T) {
+	a := newTestApp(t)
+	a.mustRun(t, exitOK, "set", fake.SampleHomeID.String(), "--name", "House", "--description", "", "--category", "public")
+
+	home := networkState(t, a, fake.SampleHomeID)
+	if home.Name != "House" || home.Description != "" || home.Category != wnlm.NLMNetworkCategoryPublic {
+		t.Errorf("home network = %+v, want it changed", home)
+	}
+	want := fake.SampleHomeID.String() + ` "Home": name "Home" -> "House", description "Home network" -> "", category Private -> Public` + "\n"
+	if got := a.stdout.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if office := networkState(t, a, fake.SampleOfficeID); office.Name != "Office" {
+		t.Errorf("office network = %+v, want it unchanged", office)
+	}
+}
+
+func TestSetDryRun(t *testing.T) {
+	a := newTestApp(t)
+	a.mustRun(t, exitOK, "set", "Home", "--name", "House", "--dry-run")
+
+	if home := networkState(t, a, fake.SampleHomeID); home.Name != "Home" {
+		t.Errorf("home network renamed to %q by a dry run", home.Name)
+	}
+	want := "(dry run) " + fake.SampleHomeID.String() + ` "Home": name "Home" -> "House"` + "\n"
+	if got := a.stdout.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSetGlob(t *testing.T) {
+	a := newTestApp(t)
+	first, second := addCorpNetworks(a)
+	a.mustRun(t, exitOK, "set", "Corp-*", "--category", "private", "--yes")
+
+	for _, id := range []wnlm.GUID{first, second} {
+		if network := networkState(t, a, id); network.Category != wnlm.NLMNetworkCategoryPrivate {
+			t.Errorf("network %s category = %s, want private", id, network.Category)
+		}
+	}
+	if home := networkState(t, a, fake.SampleHomeID); home.Category != wnlm.NLMNetworkCategoryPrivate {
+		t.Errorf("home network category = %s, want it unchanged", home.Category)
+	}
+	if office := networkState(t, a, fake.SampleOfficeID); office.Category != wnlm.NLMNetworkCategoryPublic {
+		t.Errorf("office network category = %s, want it unchanged", office.Category)
+	}
+	if n := strings.Count(a.stdout.String(), "category Public -> Private"); n != 2 {
+		t.Errorf("output = %q, want both changes", a.stdout)
+	}
+}
+
+func TestSetConfirmation(t *testing.T) {
+	tests := []struct {
+		answer  string
+		changed bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%q", test.answer), func(t *testing.T) {
+			a := newTestApp(t)
+			first, second := addCorpNetworks(a)
+			a.stdin = strings.NewReader(test.answer)
+			code := exitError
+			if test.changed {
+				code = exitOK
+			}
+			a.mustRun(t, code, "set", "Corp-?", "--description", "Corporate")
+
+			if !strings.Contains(a.stderr.String(), "Change 2 networks? [y/N] ") {
+				t.Errorf("stderr = %q, want the confirmation prompt", a.stderr)
+			}
+			if !test.changed && !strings.Contains(a.stderr.String(), "aborted") {
+				t.Errorf("stderr = %q, want the command aborted", a.stderr)
+			}
+			for _, id := range []wnlm.GUID{first, second} {
+				if changed := networkState(t, a, id).Description == "Corporate"; changed != test.changed {
+					t.Errorf("network %s changed = %t, want %t", id, changed, test.changed)
+				}
+			}
+		})
+	}
+}
+
+func TestSetElevationRequired(t *testing.T) {
+	for _, hr := range []hresult.HRESULT{hresult.E_ACCESSDENIED, hresult.E_ELEVATION_REQUIRED} {
+		a := newTestApp(t)
+		a.fake.SetFault(func(iface, method string) error {
+			if method == "SetCategory" {
+				return hr
+			}
+			return nil
+		})
+		a.mustRun(t, exitError, "set", "Home", "--category", "public")
+		if got := a.stderr.String(); !strings.Contains(got, "failed to set category") || !strings.Contains(got, "requires administrator rights") {
+			t.Errorf("%s: stderr = %q, want the error explained", hr.Name(), got)
+		}
+	}
+
+	err := explainSetError(errors.New("network not found"))
+	if strings.Contains(err.Error(), "administrator") {
+		t.Errorf("explainSetError = %q, want other errors unexplained", err)
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	tests := []struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		{[]string{"set", "Home"}, exitUsage, "nothing to set"},
+		{[]string{"set", "Home", "--category", "domain-authenticated"}, exitUsage, "invalid category"},
+		{[]string{"set", "--name", "House"}, exitUsage, "expected the GUID, name or name glob"},
+		{[]string{"set", "Cafe*", "--name", "House"}, exitError, `no network with an active connection matches "Cafe*"`},
+		{[]string{"set", "{99999999-9999-9999-9999-999999999999}", "--name", "House"}, exitError, "disconnected and remembered networks cannot be changed"},
+		{[]string{"set", "[", "--name", "House"}, exitError, "invalid network name glob"},
+	}
+	for _, test := range tests {
+		a := newTestApp(t)
+		a.mustRun(t, test.code, test.args...)
+		if !strings.Contains(a.stderr.String(), test.stderr) {
+			t.Errorf("wnlm %s stderr = %q, want it to contain %q", strings.Join(test.args, " "), a.stderr, test.stderr)
+		}
+	}
+}
+
+func TestSetNoActiveConnection(t *testing.T) {
+	a := newTestApp(t)
+	a.fake.RemoveNetwork(fake.SampleOfficeID)
+	name := "HQ"
+	err := networkChange{name: &name}.apply(a.fake, fake.SampleOfficeID)
+	if !errors.Is(err, hresult.E_NOT_FOUND) || !strings.Contains(err.Error(), "has no active connection") {
+		t.Errorf("apply() = %v, want E_NOT_FOUND explaining the network has no active connection", err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// templateFuncs are the functions available to --format templates, on top of the text/template builtins.
+var templateFuncs = template.FuncMap{
+	// hasFlag returns true if the named flag (e.g. "IPv4Internet") is set on connectivity flags.
+	"hasFlag": func(flags any, name string) (bool, error) {
+		names, err := flagNames(flags)
+		if err != nil {
+			return false, err
+		}
+		for _, flag := range names {
+			if strings.EqualFold(flag, name) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+	// flags returns the names of the flags set on connectivity (or network property change) flags.
+	"flags": flagNames,
+	// join joins the elements of a list (e.g. of GUIDs or flag names) with a separator.
+	"join": func(list any, sep string) (string, error) {
+		v := reflect.ValueOf(list)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return "", fmt.Errorf("join: expected a list but got %T", list)
+		}
+		elements := make([]string, v.Len())
+		for i := range elements {
+			elements[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(elements, sep), nil
+	},
+	// since returns the time elapsed since a timestamp, in whole seconds (zero for unset timestamps).
+	"since": func(t time.Time) time.Duration {
+		if t.IsZero() {
+			return 0
+		}
+		return time.Since(t).Round(time.Second)
+	},
+	// duration returns the compact human readable form of a duration, e.g. "3d4h" or "5m10s".
+	"duration": formatDuration,
+	// json returns the JSON encoding of a value.
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// flagNames returns the names of the flags set on connectivity (or network property change) flags.
+func flagNames(flags any) ([]string, error) {
+	switch f := flags.(type) {
+	case wnlm.NLMConnectivity:
+		return f.Flags(), nil
+	case wnlm.NLMNetworkPropertyChange:
+		if str := f.String(); str != "" {
+			return strings.Split(str, ", "), nil
+		}
+		return []string{}, nil
+	default:
+		return nil, fmt.Errorf("expected connectivity flags but got %T", flags)
+	}
+}
+
+// formatDuration returns the compact human readable form of a duration, e.g. "3d4h" or "5m10s",
+// keeping its two most significant units.
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		// the minimum duration has no positive counterpart.
+		return "-" + formatDuration(-max(d, -math.MaxInt64))
+	}
+	if d < time.Second {
+		return "0s"
+	}
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+	var b strings.Builder
+	parts := 0
+	for _, unit := range units {
+		if n := d / unit.size; n > 0 || parts > 0 {
+			if n > 0 {
+				fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+			}
+			d -= n * unit.size
+			if parts++; parts == 2 {
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// parseTemplate parses a --format template.
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("format").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// writeTemplate writes each item with a template, followed by a newline.
+func writeTemplate[T any](w io.Writer, tmpl *template.Template, items []T) error {
+	for _, item := range items {
+		if err := tmpl.Execute(w, item); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
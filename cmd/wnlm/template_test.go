@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{999 * time.Millisecond, "0s"},
+		{time.Second, "1s"},
+		{5*time.Minute + 10*time.Second + 500*time.Millisecond, "5m10s"},
+		{time.Hour + 5*time.Second, "1h"},
+		{3*24*time.Hour + 4*time.Hour + 5*time.Minute, "3d4h"},
+		{24*time.Hour + 59*time.Minute, "1d"},
+		{-90 * time.Second, "-1m30s"},
+		{time.Duration(math.MaxInt64), "106751d23h"},
+		{time.Duration(math.MinInt64), "-106751d23h"},
+	}
+	for _, test := range tests {
+		if got := formatDuration(test.d); got != test.want {
+			t.Errorf("formatDuration(%s) = %q, want %q", test.d, got, test.want)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	network := wnlm.NetworkInfo{
+		ID:            fake.SampleHomeID,
+		Name:          "Home",
+		Connectivity:  wnlm.NLMConnectivityIPv4Internet | wnlm.NLMConnectivityIPv6LocalNetwork,
+		LastConnected: time.Now().Add(-90 * time.Minute),
+		Connections:   []wnlm.GUID{fake.SampleWiFiID, fake.SampleEthernetID},
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{hasFlag .Connectivity "ipv4internet"}} {{hasFlag .Connectivity "IPv6Internet"}}`, "true false"},
+		{`{{flags .Connectivity}}`, "[IPv4Internet IPv6LocalNetwork]"},
+		{`{{join (flags .Connectivity) "|"}}`, "IPv4Internet|IPv6LocalNetwork"},
+		{`{{join .Connections ","}}`, fake.SampleWiFiID.String() + "," + fake.SampleEthernetID.String()},
+		{`{{since .LastConnected | duration}}`, "1h30m"},
+		{`{{since .Created}}`, "0s"},
+		{`{{json .Category}} {{json .Name}}`, `"Public" "Home"`},
+		{`{{upper .Name}} {{lower .Name}}`, "HOME home"},
+	}
+	for _, test := range tests {
+		tmpl, err := parseTemplate(test.template)
+		if err != nil {
+			t.Fatalf("parseTemplate(%q) failed: %v", test.template, err)
+		}
+		var b bytes.Buffer
+		if err := writeTemplate(&b, tmpl, []wnlm.NetworkInfo{network}); err != nil {
+			t.Errorf("%s failed: %v", test.template, err)
+			continue
+		}
+		if got := b.String(); got != test.want+"\n" {
+			t.Errorf("%s = %q, want %q", test.template, got, test.want)
+		}
+	}
+
+	flags, err := flagNames(wnlm.NLMNetworkPropertyChangeName | wnlm.NLMNetworkPropertyChangeCategoryValue)
+	if err != nil || strings.Join(flags, ",") != "CategoryValue,Name" {
+		t.Errorf("flagNames(property changes) = %v, %v", flags, err)
+	}
+	if flags, err := flagNames(wnlm.NLMNetworkPropertyChange(0)); err != nil || len(flags) != 0 {
+		t.Errorf("flagNames(no property changes) = %v, %v, want none", flags, err)
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	for _, text := range []string{
+		`{{hasFlag .Name "IPv4Internet"}}`,
+		`{{join .Name ","}}`,
+		`{{.SSID}}`,
+	} {
+		tmpl, err := parseTemplate(text)
+		if err != nil {
+			t.Fatalf("parseTemplate(%q) failed: %v", text, err)
+		}
+		if err := writeTemplate(&bytes.Buffer{}, tmpl, []wnlm.NetworkInfo{{Name: "Home"}}); err == nil {
+			t.Errorf("%s succeeded, want an error", text)
+		}
+	}
+	if _, err := parseTemplate(`{{nosuchfunc .Name}}`); err == nil {
+		t.Error("parseTemplate with an unknown function succeeded, want an error")
+	}
+}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// makeRaw puts the console in raw mode, with virtual terminal (ANSI) input and output,
+// and returns a function restoring its previous mode.
+func makeRaw() (func(), error) {
+	in, out := windows.Handle(os.Stdin.Fd()), windows.Handle(os.Stdout.Fd())
+	var inMode, outMode uint32
+	if err := windows.GetConsoleMode(in, &inMode); err != nil {
+		return nil, fmt.Errorf("standard input is not a console: %w", err)
+	}
+	if err := windows.GetConsoleMode(out, &outMode); err != nil {
+		return nil, fmt.Errorf("standard output is not a console: %w", err)
+	}
+	rawIn := inMode&^(windows.ENABLE_ECHO_INPUT|windows.ENABLE_PROCESSED_INPUT|windows.ENABLE_LINE_INPUT) | windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(in, rawIn); err != nil {
+		return nil, fmt.Errorf("failed to set console input mode: %w", err)
+	}
+	if err := windows.SetConsoleMode(out, outMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+		windows.SetConsoleMode(in, inMode)
+		return nil, fmt.Errorf("failed to set console output mode: %w", err)
+	}
+	return func() {
+		windows.SetConsoleMode(in, inMode)
+		windows.SetConsoleMode(out, outMode)
+	}, nil
+}
+
+// terminalSize returns the width and height, in characters, of the console window.
+func terminalSize() (width, height int, err error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(os.Stdout.Fd()), &info); err != nil {
+		return 0, 0, fmt.Errorf("failed to get console screen buffer info: %w", err)
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1, nil
+}
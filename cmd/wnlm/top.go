@@ -0,0 +1,575 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// topMaxEvents is the number of most recent events kept by the top command.
+const topMaxEvents = 100
+
+// topUpdate is an update of the state shown by the top command: a new snapshot,
+// and the events found since the previous one.
+type topUpdate struct {
+	snapshot *wnlm.Snapshot
+	events   []wnlm.Event
+	// err is the error of a failed poll, in which case the rest of the update is unset.
+	err error
+}
+
+// topSource sends updates of the state shown by the top command until ctx is done
+// (returning its error) or it fails.
+type topSource func(ctx context.Context, updates chan<- topUpdate) error
+
+// topOptions configure how the top command draws its screen.
+type topOptions struct {
+	color bool
+	// size returns the width and height of the screen.
+	size func() (width, height int)
+}
+
+// runTop runs the top command, a full screen view of networks and network connections updated live.
+func runTop(a *app, args []string) error {
+	fs := a.newFlagSet("top")
+	interval := fs.Duration("interval", time.Second, "`interval` at which to check for changes")
+	noColor := fs.Bool("no-color", false, "disable colors (also disabled by the NO_COLOR environment variable)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return a.usageError(fs, "unexpected arguments %q", positional)
+	}
+	if *interval <= 0 {
+		return a.usageError(fs, "interval must be positive")
+	}
+
+	restore, err := makeRaw()
+	if err != nil {
+		return fmt.Errorf("top requires an interactive terminal: %w", err)
+	}
+	defer restore()
+	// use the alternate screen, with a hidden cursor and no line wrapping, to leave the terminal as it was on exit.
+	fmt.Fprint(a.stdout, "\x1b[?1049h\x1b[?25l\x1b[?7l")
+	defer fmt.Fprint(a.stdout, "\x1b[?7h\x1b[?25h\x1b[?1049l")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := topOptions{color: !*noColor && os.Getenv("NO_COLOR") == "", size: screenSize}
+	return a.withManager(func(nlm wnlm.INetworkListManager) error {
+		return a.top(ctx, watcherSource(nlm, *interval), opts)
+	})
+}
+
+// screenSize returns the size of the terminal, or 80x24 if it is unknown.
+func screenSize() (width, height int) {
+	width, height, err := terminalSize()
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// watcherSource returns a topSource polling nlm with a wnlm.Watcher at the given interval.
+func watcherSource(nlm wnlm.INetworkListManager, interval time.Duration) topSource {
+	return func(ctx context.Context, updates chan<- topUpdate) error {
+		watcher := wnlm.NewWatcher(nlm, interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			update := topUpdate{}
+			// failed polls are shown, and polling carries on.
+			if update.events, update.err = watcher.Poll(); update.err == nil {
+				update.snapshot = watcher.Snapshot()
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// top runs the top command's screen with updates from source and keys read from
+// stdin, until the user quits, stdin is exhausted, ctx is done or source fails.
+func (a *app) top(ctx context.Context, source topSource, opts topOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan topUpdate)
+	errs := make(chan error, 1)
+	go func() { errs <- source(ctx, updates) }()
+	keys := readKeys(ctx, a.stdin)
+
+	model := &topModel{}
+	for {
+		width, height := opts.size()
+		if err := drawScreen(a.stdout, model.render(width, height, opts.color)); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+			// the source is done, keep showing its last update.
+			errs = nil
+		case update := <-updates:
+			model.update(update)
+		case key, ok := <-keys:
+			if !ok || model.handleKey(key) {
+				return nil
+			}
+		}
+	}
+}
+
+// drawScreen draws the lines of a screen over the previous one.
+func drawScreen(w io.Writer, lines []string) error {
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			buf.WriteString("\r\n")
+		}
+		buf.WriteString(line)
+		buf.WriteString("\x1b[K")
+	}
+	buf.WriteString("\x1b[J")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// readKeys sends the keys read from r (see parseKeys), and closes the
+// returned channel once reading fails e.g. at the end of r.
+func readKeys(ctx context.Context, r io.Reader) <-chan string {
+	keys := make(chan string)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 256)
+		for {
+			n, err := r.Read(buf)
+			for _, key := range parseKeys(buf[:n]) {
+				select {
+				case keys <- key:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// escapeKeys are the names of the keys sent as escape sequences.
+var escapeKeys = map[string]string{
+	"\x1b[A": "up",
+	"\x1b[B": "down",
+	"\x1b[C": "right",
+	"\x1b[D": "left",
+	"\x1bOA": "up",
+	"\x1bOB": "down",
+	"\x1bOC": "right",
+	"\x1bOD": "left",
+}
+
+// parseKeys returns the keys in the input of a terminal in raw mode: printable
+// characters as themselves, and "up", "down", "left", "right", "enter", "esc",
+// "backspace" or "ctrl-c". Other control characters and escape sequences are ignored.
+func parseKeys(data []byte) []string {
+	keys := []string{}
+	for len(data) > 0 {
+		if len(data) >= 3 {
+			if key, ok := escapeKeys[string(data[:3])]; ok {
+				keys = append(keys, key)
+				data = data[3:]
+				continue
+			}
+		}
+		if len(data) >= 2 && data[0] == 0x1b && data[1] == '[' {
+			// skip an unknown control sequence, up to its final byte.
+			i := 2
+			for i < len(data) && (data[i] < 0x40 || data[i] > 0x7e) {
+				i++
+			}
+			data = data[min(i+1, len(data)):]
+			continue
+		}
+		switch data[0] {
+		case 0x1b:
+			keys = append(keys, "esc")
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl-c")
+		default:
+			r, size := utf8.DecodeRune(data)
+			if unicode.IsPrint(r) {
+				keys = append(keys, string(r))
+			}
+			data = data[size:]
+			continue
+		}
+		data = data[1:]
+	}
+	return keys
+}
+
+// topModel is the state of the top command's screen.
+type topModel struct {
+	snapshot *wnlm.Snapshot
+	// events are the most recent events, oldest first.
+	events []wnlm.Event
+
+	// filter selects the networks shown, see matches.
+	filter string
+	// editing is true while the filter is being edited, and
+	// previousFilter is the filter to restore if editing is cancelled.
+	editing        bool
+	previousFilter string
+
+	// selected is the network selected in the list of networks.
+	selected wnlm.GUID
+	// detail is true when showing the details of the selected network.
+	detail bool
+
+	// err is the error of the last poll if it failed, in which case the last successful one is shown.
+	err error
+}
+
+// update applies an update from a topSource.
+func (m *topModel) update(update topUpdate) {
+	m.err = update.err
+	if update.err != nil {
+		return
+	}
+	m.snapshot = update.snapshot
+	m.events = append(m.events, update.events...)
+	if n := len(m.events) - topMaxEvents; n > 0 {
+		m.events = append([]wnlm.Event{}, m.events[n:]...)
+	}
+	if !m.detail {
+		m.selected = m.networks()[m.selectedIndex()].ID
+	}
+}
+
+// networks returns the networks matching the filter, or a single zero network if none do.
+func (m *topModel) networks() []wnlm.NetworkInfo {
+	networks := []wnlm.NetworkInfo{}
+	if m.snapshot != nil {
+		for _, network := range m.snapshot.Networks {
+			if matches(network, m.filter) {
+				networks = append(networks, network)
+			}
+		}
+	}
+	if len(networks) == 0 {
+		return []wnlm.NetworkInfo{{}}
+	}
+	return networks
+}
+
+// selectedIndex returns the index of the selected network in networks, or 0 if it is not there.
+func (m *topModel) selectedIndex() int {
+	for i, network := range m.networks() {
+		if network.ID == m.selected {
+			return i
+		}
+	}
+	return 0
+}
+
+// matches returns true if a network's name, description, GUID or category contains
+// filter, ignoring case. Every network matches an empty filter.
+func matches(network wnlm.NetworkInfo, filter string) bool {
+	filter = strings.ToLower(filter)
+	for _, s := range []string{network.Name, network.Description, network.ID.String(), network.Category.String()} {
+		if strings.Contains(strings.ToLower(s), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleKey handles a key (see parseKeys), and returns true if the user quit.
+func (m *topModel) handleKey(key string) (quit bool) {
+	if key == "ctrl-c" {
+		return true
+	}
+	if m.editing {
+		switch key {
+		case "enter":
+			m.editing = false
+		case "esc":
+			m.editing, m.filter = false, m.previousFilter
+		case "backspace":
+			if _, size := utf8.DecodeLastRuneInString(m.filter); size > 0 {
+				m.filter = m.filter[:len(m.filter)-size]
+			}
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				m.filter += key
+			}
+		}
+		m.selected = m.networks()[m.selectedIndex()].ID
+		return false
+	}
+
+	switch key {
+	case "q":
+		return true
+	case "/":
+		m.editing, m.previousFilter, m.detail = true, m.filter, false
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "enter", "right", "l":
+		m.detail = !m.selected.IsZero()
+	case "esc", "backspace", "left", "h":
+		if m.detail {
+			m.detail = false
+		} else if key == "esc" {
+			m.filter = ""
+		}
+	}
+	return false
+}
+
+// move moves the selection in the list of networks by delta, unless showing the details of a network.
+func (m *topModel) move(delta int) {
+	if m.detail {
+		return
+	}
+	networks := m.networks()
+	i := max(0, min(len(networks)-1, m.selectedIndex()+delta))
+	m.selected = networks[i].ID
+}
+
+// render returns the lines of the screen, at most height lines of at most width characters.
+func (m *topModel) render(width, height int, color bool) []string {
+	s := &screen{width: width, color: color}
+	title := "wnlm top"
+	if m.snapshot != nil {
+		title = fmt.Sprintf("wnlm top - %d networks, %d connections - %s",
+			len(m.snapshot.Networks), len(m.snapshot.Connections), m.snapshot.Time.Local().Format(time.TimeOnly))
+	}
+	s.line(s.style("1", title))
+
+	footer, footerStyle := "q quit  / filter  ↑/↓ select  enter details", "2"
+	switch {
+	case m.editing:
+		footer = fmt.Sprintf("filter: %s_  (enter apply, esc cancel)", m.filter)
+	case m.detail:
+		footer = "esc back  q quit"
+	case m.err != nil:
+		footer, footerStyle = fmt.Sprintf("poll failed: %v  ", m.err)+footer, "31"
+	case m.filter != "":
+		footer = fmt.Sprintf("filter: %q  esc clear  ", m.filter) + footer
+	}
+
+	body := height - 2
+	switch {
+	case m.snapshot == nil:
+		s.line("waiting for the state of networks...")
+	case m.detail:
+		m.renderDetail(s, body)
+	default:
+		m.renderList(s, body)
+	}
+	for len(s.lines) < height-1 {
+		s.line("")
+	}
+	s.lines = s.lines[:max(0, height-1)]
+	s.line(s.style(footerStyle, footer))
+	return s.lines[:max(0, height)]
+}
+
+// renderList renders the list of networks, followed by the most recent events, in the given number of lines.
+func (m *topModel) renderList(s *screen, lines int) {
+	events := min(len(m.events), max(0, (lines-2)/3))
+	rows := lines - 1
+	if events > 0 {
+		rows -= events + 2
+	}
+
+	const fixed = 2 + 22 + 22 + 13 + 13 + 6
+	nameWidth := max(10, s.width-fixed)
+	s.line(s.style("1", "  "+s.cell("NAME", nameWidth, "")+s.cell("CATEGORY", 22, "")+s.cell("DOMAIN TYPE", 22, "")+
+		s.cell("IPV4", 13, "")+s.cell("IPV6", 13, "")+s.cell("CONNS", 6, "")))
+
+	networks := m.networks()
+	if networks[0].ID.IsZero() {
+		s.line("  (no networks)")
+		rows--
+		networks = nil
+	}
+	selected := m.selectedIndex()
+	offset := max(0, selected-rows+1)
+	for i := offset; i < len(networks) && i < offset+rows; i++ {
+		network := networks[i]
+		marker, nameStyle := "  ", ""
+		if i == selected {
+			marker, nameStyle = "> ", "7"
+		}
+		s.line(marker + s.cell(network.Name, nameWidth, nameStyle) +
+			s.cell(network.Category.String(), 22, categoryStyle(network.Category)) +
+			s.cell(network.DomainType.String(), 22, "") +
+			s.cell(network.Connectivity.IPv4Level().String(), 13, levelStyle(network.Connectivity.IPv4Level())) +
+			s.cell(network.Connectivity.IPv6Level().String(), 13, levelStyle(network.Connectivity.IPv6Level())) +
+			s.cell(fmt.Sprint(len(network.Connections)), 6, ""))
+	}
+
+	if events > 0 {
+		for len(s.lines) < lines-events {
+			s.line("")
+		}
+		m.renderEvents(s, m.events[len(m.events)-events:])
+	}
+}
+
+// renderDetail renders the details of the selected network, its connections and its most
+// recent events, in the given number of lines.
+func (m *topModel) renderDetail(s *screen, lines int) {
+	network, ok := m.snapshot.Network(m.selected)
+	if !ok {
+		s.line(fmt.Sprintf("network %s is gone", m.selected))
+		return
+	}
+	fields := []struct{ name, value, style string }{
+		{"Name", network.Name, "1"},
+		{"ID", network.ID.String(), ""},
+		{"Description", network.Description, ""},
+		{"Category", network.Category.String(), categoryStyle(network.Category)},
+		{"Domain type", network.DomainType.String(), ""},
+		{"IPv4", network.Connectivity.IPv4Level().String(), levelStyle(network.Connectivity.IPv4Level())},
+		{"IPv6", network.Connectivity.IPv6Level().String(), levelStyle(network.Connectivity.IPv6Level())},
+		{"Connected", fmt.Sprint(network.Connected), ""},
+		{"Internet", fmt.Sprint(network.ConnectedToInternet), ""},
+		{"Created", formatTime(network.Created), ""},
+		{"Last connected", formatTime(network.LastConnected), ""},
+	}
+	for _, field := range fields {
+		s.line(s.cell(field.name, 16, "") + s.cell(field.value, s.width-16, field.style))
+	}
+
+	s.line("")
+	s.line(s.style("1", s.cell("CONNECTION", 40, "")+s.cell("ADAPTER", 40, "")+s.cell("IPV4", 13, "")+s.cell("IPV6", 13, "")))
+	for _, conn := range m.snapshot.ConnectionsOf(network.ID) {
+		s.line(s.cell(conn.ID.String(), 40, "") + s.cell(conn.AdapterID.String(), 40, "") +
+			s.cell(conn.Connectivity.IPv4Level().String(), 13, levelStyle(conn.Connectivity.IPv4Level())) +
+			s.cell(conn.Connectivity.IPv6Level().String(), 13, levelStyle(conn.Connectivity.IPv6Level())))
+	}
+
+	events := []wnlm.Event{}
+	for _, event := range m.events {
+		if event.NetworkID == network.ID {
+			events = append(events, event)
+		}
+	}
+	if n := min(len(events), lines-len(s.lines)-2); n > 0 {
+		s.line("")
+		m.renderEvents(s, events[len(events)-n:])
+	}
+}
+
+// renderEvents renders a title followed by the given events.
+func (m *topModel) renderEvents(s *screen, events []wnlm.Event) {
+	s.line(s.style("1", "RECENT EVENTS"))
+	for _, event := range events {
+		s.line(s.cell(formatEvent(event), s.width, ""))
+	}
+}
+
+// formatTime returns the local form of a time, or "-" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// levelStyle returns the style of a connectivity level: green for Internet, yellow
+// for local connectivity, and red for no traffic or no connectivity.
+func levelStyle(level wnlm.ConnectivityLevel) string {
+	switch {
+	case level.AtLeast(wnlm.ConnectivityLevelInternet):
+		return "32"
+	case level.AtLeast(wnlm.ConnectivityLevelSubnet):
+		return "33"
+	default:
+		return "31"
+	}
+}
+
+// categoryStyle returns the style of a network category.
+func categoryStyle(category wnlm.NLMNetworkCategory) string {
+	switch category {
+	case wnlm.NLMNetworkCategoryPublic:
+		return "33"
+	case wnlm.NLMNetworkCategoryPrivate:
+		return "32"
+	case wnlm.NLMNetworkCategoryDomainAuthenticated:
+		return "36"
+	default:
+		return ""
+	}
+}
+
+// screen accumulates the lines of a screen.
+type screen struct {
+	width int
+	color bool
+	lines []string
+}
+
+// line adds a line to the screen.
+func (s *screen) line(line string) {
+	s.lines = append(s.lines, line)
+}
+
+// style returns text with the given SGR style e.g. "1" for bold, if colors are enabled.
+func (s *screen) style(style, text string) string {
+	if !s.color || style == "" {
+		return text
+	}
+	return "\x1b[" + style + "m" + text + "\x1b[0m"
+}
+
+// cell returns text truncated or padded to width characters, with the given style (see style).
+func (s *screen) cell(text string, width int, style string) string {
+	if width <= 0 {
+		return ""
+	}
+	// leave a space between cells, truncating text with an ellipsis if need be.
+	runes := []rune(text)
+	if len(runes) > width-1 {
+		runes = append(runes[:max(0, width-2)], '…')
+	}
+	return s.style(style, string(runes)) + strings.Repeat(" ", width-len(runes))
+}
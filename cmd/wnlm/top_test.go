@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+func TestWatcherSourceKeepsPolling(t *testing.T) {
+	a := newTestApp(t)
+	failFirstPolls(a, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan topUpdate)
+	done := make(chan error)
+	go func() { done <- watcherSource(a.fake, time.Millisecond)(ctx, updates) }()
+
+	model := &topModel{}
+	model.update(<-updates)
+	if model.err == nil || model.snapshot != nil {
+		t.Fatalf("first update = %v, %+v, want the failed poll", model.err, model.snapshot)
+	}
+	if footer := model.render(80, 10, false)[9]; !strings.HasPrefix(footer, "poll failed: ") {
+		t.Errorf("footer = %q, want the failed poll", footer)
+	}
+	model.update(<-updates)
+	if model.err != nil || model.snapshot == nil || len(model.snapshot.Networks) != 2 {
+		t.Fatalf("second update = %v, %+v, want a snapshot", model.err, model.snapshot)
+	}
+	if footer := model.render(80, 10, false)[9]; strings.HasPrefix(footer, "poll failed: ") {
+		t.Errorf("footer = %q, want the failed poll cleared", footer)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("watcherSource = %v, want context.Canceled", err)
+	}
+}
+
+// testSnapshot returns a snapshot of the fake manager of a.
+func testSnapshot(t *testing.T, a *testApp) *wnlm.Snapshot {
+	t.Helper()
+	snapshot, err := wnlm.TakeSnapshot(a.fake)
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	return snapshot
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("q/é\x1b[A\x1bOB\r\n\x7f\x08\x03\x1b\x1b[1;5Z\x01j"))
+	want := []string{"q", "/", "é", "up", "down", "enter", "enter", "backspace", "backspace", "ctrl-c", "esc", "j"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("parseKeys = %q, want %q", got, want)
+	}
+}
+
+func TestTopModelKeys(t *testing.T) {
+	a := newTestApp(t)
+	model := &topModel{}
+	model.update(topUpdate{snapshot: testSnapshot(t, a)})
+	if model.selected != fake.SampleHomeID {
+		t.Fatalf("selected = %s, want the first network", model.selected)
+	}
+
+	model.handleKey("down")
+	model.handleKey("j")
+	if model.selected != fake.SampleOfficeID {
+		t.Errorf("selected = %s after moving down twice, want the last network", model.selected)
+	}
+	model.handleKey("enter")
+	if !model.detail {
+		t.Error("enter did not show the details of the selected network")
+	}
+	model.handleKey("up")
+	if model.selected != fake.SampleOfficeID {
+		t.Errorf("selected = %s, want the selection kept while showing details", model.selected)
+	}
+	model.handleKey("esc")
+	if model.detail {
+		t.Error("esc did not go back to the list")
+	}
+
+	for _, key := range []string{"/", "p", "r", "x", "backspace", "i", "enter"} {
+		model.handleKey(key)
+	}
+	if model.filter != "pri" || model.editing || model.selected != fake.SampleHomeID {
+		t.Errorf("filter = %q, editing = %t, selected = %s, want private networks selected", model.filter, model.editing, model.selected)
+	}
+	for _, key := range []string{"/", "o", "esc"} {
+		model.handleKey(key)
+	}
+	if model.filter != "pri" {
+		t.Errorf("filter = %q after cancelling an edit, want it restored", model.filter)
+	}
+	model.handleKey("esc")
+	if model.filter != "" {
+		t.Errorf("filter = %q after esc, want it cleared", model.filter)
+	}
+
+	if model.handleKey("x") || !model.handleKey("q") || !model.handleKey("ctrl-c") {
+		t.Error("handleKey did not quit only on q and ctrl-c")
+	}
+	model.editing = true
+	if model.handleKey("q") || !model.handleKey("ctrl-c") {
+		t.Error("handleKey did not quit only on ctrl-c while editing the filter")
+	}
+}
+
+func TestTopModelEvents(t *testing.T) {
+	model := &topModel{}
+	for i := 0; i < topMaxEvents+10; i++ {
+		model.update(topUpdate{snapshot: &wnlm.Snapshot{}, events: []wnlm.Event{{Name: fmt.Sprint(i)}}})
+	}
+	if len(model.events) != topMaxEvents || model.events[0].Name != "10" {
+		t.Errorf("kept %d events from %q, want the %d most recent", len(model.events), model.events[0].Name, topMaxEvents)
+	}
+}
+
+func TestTopModelRender(t *testing.T) {
+	a := newTestApp(t)
+	model := &topModel{}
+	if lines := model.render(80, 5, false); len(lines) != 5 || lines[1] != "waiting for the state of networks..." {
+		t.Errorf("render before any update = %q", lines)
+	}
+
+	snapshot := testSnapshot(t, a)
+	event := wnlm.Event{Kind: wnlm.EventNetworkConnectivityChanged, ID: fake.SampleHomeID, NetworkID: fake.SampleHomeID, Name: "Home", Time: snapshot.Time}
+	model.update(topUpdate{snapshot: snapshot, events: []wnlm.Event{event}})
+
+	lines := model.render(120, 12, false)
+	if len(lines) != 12 {
+		t.Fatalf("render = %d lines, want 12", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "wnlm top - 2 networks, 2 connections - ") {
+		t.Errorf("title = %q", lines[0])
+	}
+	want := []string{
+		"  NAME                                      CATEGORY              DOMAIN TYPE           IPV4         IPV6         CONNS ",
+		"> Home                                      Private               None                  Internet     LocalNetwork 1     ",
+		"  Office                                    Public                Domain Authenticated  Disconnected Disconnected 1     ",
+	}
+	for i, line := range want {
+		if lines[1+i] != line {
+			t.Errorf("line %d = %q, want %q", 1+i, lines[1+i], line)
+		}
+	}
+	if lines[9] != "RECENT EVENTS" || !strings.Contains(lines[10], "NetworkConnectivityChanged") {
+		t.Errorf("events = %q, want the event", lines[9:11])
+	}
+	if lines[11] != "q quit  / filter  ↑/↓ select  enter details" {
+		t.Errorf("footer = %q", lines[11])
+	}
+	for i, line := range lines {
+		if n := utf8.RuneCountInString(line); n > 120 {
+			t.Errorf("line %d is %d characters wide, want at most 120", i, n)
+		}
+		if strings.Contains(line, "\x1b") {
+			t.Errorf("line %d = %q, want no colors", i, line)
+		}
+	}
+
+	colored := model.render(120, 12, true)
+	if !strings.Contains(colored[2], "\x1b[7mHome") || !strings.Contains(colored[2], "\x1b[32mPrivate") {
+		t.Errorf("selected line = %q, want it colored", colored[2])
+	}
+
+	model.handleKey("enter")
+	lines = model.render(120, 20, false)
+	for _, want := range []string{"Name            Home", "Description     Home network", "IPv6            LocalNetwork", "{AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA}", "RECENT EVENTS"} {
+		if !strings.Contains(strings.Join(lines, "\n"), want) {
+			t.Errorf("details = %q, want %q", lines, want)
+		}
+	}
+	if lines[19] != "esc back  q quit" {
+		t.Errorf("footer = %q", lines[19])
+	}
+
+	model.handleKey("esc")
+	for _, key := range []string{"/", "c", "a", "f", "e"} {
+		model.handleKey(key)
+	}
+	lines = model.render(120, 6, false)
+	if lines[2] != "  (no networks)" || lines[5] != "filter: cafe_  (enter apply, esc cancel)" {
+		t.Errorf("filtered = %q, want no networks", lines)
+	}
+
+	if lines := model.render(20, 2, false); len(lines) != 2 {
+		t.Errorf("render in a tiny screen = %q, want 2 lines", lines)
+	}
+}
+
+func TestTop(t *testing.T) {
+	a := newTestApp(t)
+	snapshot := testSnapshot(t, a)
+	source := func(ctx context.Context, updates chan<- topUpdate) error {
+		select {
+		case updates <- topUpdate{snapshot: snapshot}:
+		case <-ctx.Done():
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	// stdin is read once the first update is drawn
+	a.stdin = &delayedReader{Reader: strings.NewReader("jq"), delay: 100 * time.Millisecond}
+	opts := topOptions{size: func() (int, int) { return 120, 10 }}
+	if err := a.top(context.Background(), source, opts); err != nil {
+		t.Fatalf("top failed: %v", err)
+	}
+	screens := strings.Split(a.stdout.String(), "\x1b[H")
+	last := screens[len(screens)-1]
+	if !strings.Contains(last, "> Office") || !strings.HasSuffix(last, "\x1b[K\x1b[J") {
+		t.Errorf("last screen = %q, want the office network selected", last)
+	}
+
+	errSource := errors.New("source failed")
+	a = newTestApp(t)
+	a.stdin = &delayedReader{Reader: strings.NewReader(""), delay: time.Hour}
+	err := a.top(context.Background(), func(context.Context, chan<- topUpdate) error { return errSource }, opts)
+	if !errors.Is(err, errSource) {
+		t.Errorf("top = %v, want %v", err, errSource)
+	}
+}
+
+// delayedReader is an io.Reader which waits before its first read.
+type delayedReader struct {
+	io.Reader
+	delay time.Duration
+	once  sync.Once
+}
+
+func (r *delayedReader) Read(p []byte) (int, error) {
+	r.once.Do(func() { time.Sleep(r.delay) })
+	return r.Reader.Read(p)
+}
@@ -0,0 +1,20 @@
+package main
+
+import "github.com/adrianosela/wnlm"
+
+// runTree runs the tree command, which outputs the tree of networks, network connections and adapters.
+func runTree(a *app, args []string) error {
+	fs := a.newFlagSet("tree")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return a.usageError(fs, "unexpected arguments %q", positional)
+	}
+	snapshot, err := a.snapshot()
+	if err != nil {
+		return err
+	}
+	return wnlm.WriteTree(a.stdout, snapshot)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// exitTimeout is the exit code of the wait command when it times out.
+const exitTimeout = 3
+
+// condition is a condition on the state of networks, which the wait command waits for.
+type condition struct {
+	name string
+	met  func(*wnlm.Snapshot) bool
+}
+
+// parseCondition parses a condition of the wait command.
+func parseCondition(s string) (condition, error) {
+	anyConnection := func(s string, fn func(wnlm.NLMConnectivity) bool) condition {
+		return condition{name: s, met: func(snapshot *wnlm.Snapshot) bool {
+			for _, conn := range snapshot.Connections {
+				if fn(conn.Connectivity) {
+					return true
+				}
+			}
+			return false
+		}}
+	}
+	internet := func(family wnlm.IPFamily) func(wnlm.NLMConnectivity) bool {
+		return func(c wnlm.NLMConnectivity) bool { return c.AtLeast(family, wnlm.ConnectivityLevelInternet) }
+	}
+
+	switch {
+	case s == "ipv4-internet":
+		return anyConnection(s, internet(wnlm.IPFamilyIPv4)), nil
+	case s == "ipv6-internet":
+		return anyConnection(s, internet(wnlm.IPFamilyIPv6)), nil
+	case s == "any-internet":
+		return anyConnection(s, func(c wnlm.NLMConnectivity) bool {
+			return internet(wnlm.IPFamilyIPv4)(c) || internet(wnlm.IPFamilyIPv6)(c)
+		}), nil
+	case s == "connected":
+		return anyConnection(s, func(c wnlm.NLMConnectivity) bool { return !c.IsDisconnected() }), nil
+	case strings.HasPrefix(s, "network="):
+		name := strings.TrimPrefix(s, "network=")
+		if name == "" {
+			return condition{}, fmt.Errorf("missing network name in %q", s)
+		}
+		return condition{name: s, met: func(snapshot *wnlm.Snapshot) bool {
+			for _, network := range snapshot.Networks {
+				if network.Name == name && !network.Connectivity.IsDisconnected() {
+					return true
+				}
+			}
+			return false
+		}}, nil
+	default:
+		return condition{}, fmt.Errorf("unknown condition %q, expected ipv4-internet, ipv6-internet, any-internet, connected or network=<name>", s)
+	}
+}
+
+// runWait runs the wait command, which waits until a condition on the state of networks is met.
+// It exits with exitOK once the condition is met, exitTimeout if it times out and exitError on
+// errors, including transient ones the last check before the timeout failed with.
+func runWait(a *app, args []string) error {
+	fs := a.newFlagSet("wait")
+	forCondition := fs.String("for", "", "`condition` to wait for: ipv4-internet, ipv6-internet, any-internet, connected or network=<name>")
+	timeout := fs.Duration("timeout", 0, "maximum `duration` to wait for (default: no limit)")
+	interval := fs.Duration("interval", time.Second, "`interval` at which to check the condition")
+	quiet := fs.Bool("q", false, "do not print anything once the condition is met")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return a.usageError(fs, "unexpected arguments %q", positional)
+	}
+	if *forCondition == "" {
+		return a.usageError(fs, "missing --for condition")
+	}
+	cond, err := parseCondition(*forCondition)
+	if err != nil {
+		return a.usageError(fs, "%v", err)
+	}
+	if *interval <= 0 || *timeout < 0 {
+		return a.usageError(fs, "interval must be positive and timeout must not be negative")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	return a.withManager(func(nlm wnlm.INetworkListManager) error {
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		// transient failures to check the condition (e.g. while the network stack restarts)
+		// are reported once each, and the condition is checked again until the timeout.
+		var lastErr error
+		for {
+			snapshot, err := wnlm.TakeSnapshot(nlm)
+			switch {
+			case err != nil && !wnlm.IsTransient(err):
+				return fmt.Errorf("failed to check %s: %w", cond.name, err)
+			case err != nil:
+				if lastErr == nil || err.Error() != lastErr.Error() {
+					fmt.Fprintf(a.stderr, "wnlm: failed to check %s: %v\n", cond.name, err)
+				}
+				lastErr = err
+			case cond.met(snapshot):
+				if !*quiet {
+					fmt.Fprintf(a.stdout, "%s after %s\n", cond.name, time.Since(start).Round(time.Millisecond))
+				}
+				return nil
+			default:
+				lastErr = nil
+			}
+			select {
+			case <-ctx.Done():
+				if ctx.Err() == context.DeadlineExceeded {
+					if lastErr != nil {
+						return fmt.Errorf("timed out after %s waiting for %s, last check failed: %w", *timeout, cond.name, lastErr)
+					}
+					return &exitCodeError{code: exitTimeout, err: fmt.Errorf("timed out after %s waiting for %s", *timeout, cond.name)}
+				}
+				return &exitCodeError{code: exitError, err: fmt.Errorf("interrupted while waiting for %s", cond.name)}
+			case <-ticker.C:
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+func TestWaitMet(t *testing.T) {
+	a := newTestApp(t)
+	a.mustRun(t, exitOK, "wait", "--for", "ipv4-internet")
+	if got := a.stdout.String(); !strings.HasPrefix(got, "ipv4-internet after ") {
+		t.Errorf("output = %q, want the condition met", got)
+	}
+
+	a = newTestApp(t)
+	a.mustRun(t, exitOK, "wait", "--for", "network=Home", "-q")
+	if a.stdout.Len() != 0 {
+		t.Errorf("output = %q, want none", a.stdout)
+	}
+}
+
+func TestWaitKeepsPolling(t *testing.T) {
+	a := newTestApp(t)
+	failFirstPolls(a, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.fake.Play(ctx, []fake.Step{{After: fake.Duration(100 * time.Millisecond), SetNetwork: lanStep.SetNetwork, SetConnection: lanStep.SetConnection}})
+
+	a.mustRun(t, exitOK, "wait", "--for", "network=LAN", "--interval", "10ms", "--timeout", "10s")
+	if got := a.stdout.String(); !strings.HasPrefix(got, "network=LAN after ") {
+		t.Errorf("output = %q, want the condition met", got)
+	}
+	// the same error is only reported once
+	if got := a.stderr.String(); strings.Count(got, "wnlm: failed to check network=LAN: ") != 1 {
+		t.Errorf("stderr = %q, want the failed checks reported once", got)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	a := newTestApp(t)
+	a.mustRun(t, exitTimeout, "wait", "--for", "ipv6-internet", "--interval", "10ms", "--timeout", "50ms")
+	if got := a.stderr.String(); !strings.Contains(got, "timed out after 50ms waiting for ipv6-internet\n") {
+		t.Errorf("stderr = %q, want the timeout", got)
+	}
+
+	// the last check failed
+	a = newTestApp(t)
+	failFirstPolls(a, 1<<30)
+	a.mustRun(t, exitError, "wait", "--for", "connected", "--interval", "10ms", "--timeout", "50ms")
+	if got := a.stderr.String(); !strings.Contains(got, "timed out after 50ms waiting for connected, last check failed: ") {
+		t.Errorf("stderr = %q, want the timeout with the last failure", got)
+	}
+}
+
+func TestWaitErrors(t *testing.T) {
+	a := newTestApp(t)
+	a.newManager = func() (wnlm.INetworkListManager, func(), error) {
+		return nil, nil, errors.New("failed to initialize session")
+	}
+	a.mustRun(t, exitError, "wait", "--for", "connected")
+	if got := a.stderr.String(); got != "wnlm: failed to initialize session\n" {
+		t.Errorf("stderr = %q, want the error", got)
+	}
+
+	// failures which are not transient are not retried, even without a timeout
+	a = newTestApp(t)
+	a.fake.SetFault(func(iface, method string) error { return hresult.E_ACCESSDENIED })
+	a.mustRun(t, exitError, "wait", "--for", "connected", "--interval", "10ms")
+	if got := a.stderr.String(); strings.Count(got, "wnlm: failed to check connected: ") != 1 {
+		t.Errorf("stderr = %q, want the failure", got)
+	}
+
+	for _, args := range [][]string{
+		{"wait"},
+		{"wait", "--for", "wifi"},
+		{"wait", "--for", "network="},
+		{"wait", "--for", "connected", "--interval", "0s"},
+		{"wait", "--for", "connected", "--timeout", "-1s"},
+	} {
+		a := newTestApp(t)
+		a.mustRun(t, exitUsage, args...)
+	}
+}
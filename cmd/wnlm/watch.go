@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// runWatch runs the watch command, which outputs changes to networks and network connections as they happen.
+func runWatch(a *app, args []string) error {
+	fs := a.newFlagSet("watch")
+	network := fs.String("network", "", "only output changes to the network with the given `name or GUID` (and to its connections)")
+	once := fs.Bool("once", false, "exit after the first change output")
+	output := fs.String("o", formatTable, "output `format`: table (one line per change) or ndjson")
+	interval := fs.Duration("interval", time.Second, "`interval` at which to check for changes")
+	format := fs.String("format", "", "Go `template` to output each change (a wnlm.Event) with, overriding -o")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return a.usageError(fs, "unexpected arguments %q", positional)
+	}
+	if *output != formatTable && *output != formatNDJSON {
+		return a.usageError(fs, "unknown output format %q", *output)
+	}
+	var tmpl *template.Template
+	if *format != "" {
+		if tmpl, err = parseTemplate(*format); err != nil {
+			return a.usageError(fs, "invalid format template: %v", err)
+		}
+	}
+	if *interval <= 0 {
+		return a.usageError(fs, "interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return a.withManager(func(nlm wnlm.INetworkListManager) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var writeErr error
+		watcher := wnlm.NewWatcher(nlm, *interval, wnlm.WithPollErrorHandler(func(err error) {
+			fmt.Fprintf(a.stderr, "wnlm: failed to poll for changes: %v\n", err)
+		}))
+		watcher.Subscribe(func(event wnlm.Event) {
+			if ctx.Err() != nil || !matchesNetwork(event, *network) {
+				return
+			}
+			if tmpl != nil {
+				writeErr = writeTemplate(a.stdout, tmpl, []wnlm.Event{event})
+			} else {
+				writeErr = writeEvent(a, *output, event)
+			}
+			if writeErr != nil || *once {
+				cancel()
+			}
+		})
+		watcher.Run(ctx)
+		return writeErr
+	})
+}
+
+// matchesNetwork returns true if an event is about the network with the given name
+// or GUID, or one of its connections. Any event matches an empty filter.
+func matchesNetwork(event wnlm.Event, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	if id, err := wnlm.ParseGUID(filter); err == nil {
+		return event.NetworkID == id || event.ID == id
+	}
+	return event.Name == filter
+}
+
+// writeEvent writes an event in the chosen output format.
+func writeEvent(a *app, format string, event wnlm.Event) error {
+	if format == formatNDJSON {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(a.stdout, "%s\n", data)
+		return err
+	}
+	_, err := fmt.Fprintln(a.stdout, formatEvent(event))
+	return err
+}
+
+// formatEvent returns the single line, human readable, form of an event.
+func formatEvent(event wnlm.Event) string {
+	parts := []string{
+		event.Time.Local().Format(time.RFC3339),
+		event.Kind.String(),
+		event.ID.String(),
+		fmt.Sprintf("%q", event.Name),
+	}
+	switch event.Kind {
+	case wnlm.EventNetworkConnectivityChanged, wnlm.EventConnectionConnectivityChanged:
+		parts = append(parts, fmt.Sprintf("%s -> %s", formatConnectivity(event.PreviousConnectivity), formatConnectivity(event.Connectivity)))
+	case wnlm.EventNetworkAdded, wnlm.EventConnectionAdded:
+		parts = append(parts, formatConnectivity(event.Connectivity))
+	case wnlm.EventNetworkDeleted, wnlm.EventConnectionDeleted:
+		parts = append(parts, formatConnectivity(event.PreviousConnectivity))
+	case wnlm.EventNetworkPropertyChanged:
+		parts = append(parts, event.Properties.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatConnectivity returns the compact form of connectivity flags, e.g. "IPv4Internet|IPv6Subnet".
+func formatConnectivity(connectivity wnlm.NLMConnectivity) string {
+	if flags := connectivity.Flags(); len(flags) > 0 {
+		return strings.Join(flags, "|")
+	}
+	return connectivity.String()
+}
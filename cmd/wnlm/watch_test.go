@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+var lanID = wnlm.MustParseGUID("{33333333-3333-3333-3333-333333333333}")
+
+// lanStep is a script step adding a connected LAN network, with a connection.
+var lanStep = fake.Step{
+	SetNetwork:    &fake.Network{ID: lanID, Name: "LAN", Category: wnlm.NLMNetworkCategoryPrivate, Connectivity: wnlm.NLMConnectivityIPv4LocalNetwork},
+	SetConnection: &fake.Connection{ID: lanID, AdapterID: fake.SampleAdapterID, NetworkID: lanID, Connectivity: wnlm.NLMConnectivityIPv4LocalNetwork},
+}
+
+// failFirstPolls makes the first n enumerations of the fake manager of a fail.
+func failFirstPolls(a *testApp, n int32) {
+	var polls atomic.Int32
+	a.fake.SetFault(func(iface, method string) error {
+		if method == "GetNetworkConnections" && polls.Add(1) <= n {
+			return hresult.RPC_E_DISCONNECTED
+		}
+		return nil
+	})
+}
+
+func TestWatchKeepsPolling(t *testing.T) {
+	a := newTestApp(t)
+	failFirstPolls(a, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.fake.Play(ctx, []fake.Step{{After: fake.Duration(200 * time.Millisecond), SetNetwork: lanStep.SetNetwork, SetConnection: lanStep.SetConnection}})
+
+	a.mustRun(t, exitOK, "watch", "--once", "--interval", "10ms", "--network", "LAN", "-o", "ndjson")
+	if got := a.stdout.String(); !strings.Contains(got, `"kind":"NetworkAdded"`) || strings.Count(got, "\n") != 1 {
+		t.Errorf("output = %q, want the LAN network added", got)
+	}
+	if got := a.stderr.String(); strings.Count(got, "wnlm: failed to poll for changes: ") != 2 {
+		t.Errorf("stderr = %q, want both failed polls", got)
+	}
+}
@@ -0,0 +1,135 @@
+package wnlm
+
+// IPFamily represents an IP address family as reported by NLMConnectivity.
+type IPFamily int
+
+const (
+	// IPFamilyIPv4 represents the IPv4 address family.
+	IPFamilyIPv4 = IPFamily(4)
+	// IPFamilyIPv6 represents the IPv6 address family.
+	IPFamilyIPv6 = IPFamily(6)
+)
+
+// String returns the string representation of the IPFamily.
+func (f IPFamily) String() string {
+	switch f {
+	case IPFamilyIPv4:
+		return "IPv4"
+	case IPFamilyIPv6:
+		return "IPv6"
+	default:
+		return ""
+	}
+}
+
+// ConnectivityLevel represents the connectivity of a single IP address family
+// as an ordered level, such that a greater level implies more connectivity.
+type ConnectivityLevel int
+
+const (
+	// ConnectivityLevelDisconnected represents no connectivity.
+	ConnectivityLevelDisconnected = ConnectivityLevel(0)
+	// ConnectivityLevelNoTraffic represents connectivity with no traffic detected.
+	ConnectivityLevelNoTraffic = ConnectivityLevel(1)
+	// ConnectivityLevelSubnet represents connectivity to the local subnet.
+	ConnectivityLevelSubnet = ConnectivityLevel(2)
+	// ConnectivityLevelLocalNetwork represents connectivity to a routed local network.
+	ConnectivityLevelLocalNetwork = ConnectivityLevel(3)
+	// ConnectivityLevelInternet represents connectivity to the Internet.
+	ConnectivityLevelInternet = ConnectivityLevel(4)
+)
+
+var connectivityLevelToString = map[ConnectivityLevel]string{
+	ConnectivityLevelDisconnected: "Disconnected",
+	ConnectivityLevelNoTraffic:    "NoTraffic",
+	ConnectivityLevelSubnet:       "Subnet",
+	ConnectivityLevelLocalNetwork: "LocalNetwork",
+	ConnectivityLevelInternet:     "Internet",
+}
+
+// String returns the string representation of the ConnectivityLevel.
+func (l ConnectivityLevel) String() string {
+	if str, ok := connectivityLevelToString[l]; ok {
+		return str
+	}
+	return ""
+}
+
+// Compare returns -1, 0 or +1 depending on whether l is lower than,
+// equal to or greater than other.
+func (l ConnectivityLevel) Compare(other ConnectivityLevel) int {
+	switch {
+	case l < other:
+		return -1
+	case l > other:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast returns true if l is greater than or equal to min.
+func (l ConnectivityLevel) AtLeast(min ConnectivityLevel) bool {
+	return l >= min
+}
+
+// IPv4Level returns the highest IPv4 ConnectivityLevel set on the NLMConnectivity.
+func (c NLMConnectivity) IPv4Level() ConnectivityLevel {
+	switch {
+	case c.IsIPv4Internet():
+		return ConnectivityLevelInternet
+	case c.IsIPv4LocalNetwork():
+		return ConnectivityLevelLocalNetwork
+	case c.IsIPv4Subnet():
+		return ConnectivityLevelSubnet
+	case c.IsIPv4NoTraffic():
+		return ConnectivityLevelNoTraffic
+	default:
+		return ConnectivityLevelDisconnected
+	}
+}
+
+// IPv6Level returns the highest IPv6 ConnectivityLevel set on the NLMConnectivity.
+func (c NLMConnectivity) IPv6Level() ConnectivityLevel {
+	switch {
+	case c.IsIPv6Internet():
+		return ConnectivityLevelInternet
+	case c.IsIPv6LocalNetwork():
+		return ConnectivityLevelLocalNetwork
+	case c.IsIPv6Subnet():
+		return ConnectivityLevelSubnet
+	case c.IsIPv6NoTraffic():
+		return ConnectivityLevelNoTraffic
+	default:
+		return ConnectivityLevelDisconnected
+	}
+}
+
+// Level returns the highest ConnectivityLevel set on the NLMConnectivity for the
+// given IPFamily. Unknown families are always considered disconnected.
+func (c NLMConnectivity) Level(family IPFamily) ConnectivityLevel {
+	switch family {
+	case IPFamilyIPv4:
+		return c.IPv4Level()
+	case IPFamilyIPv6:
+		return c.IPv6Level()
+	default:
+		return ConnectivityLevelDisconnected
+	}
+}
+
+// AtLeast returns true if the NLMConnectivity has at least the given
+// ConnectivityLevel for the given IPFamily.
+func (c NLMConnectivity) AtLeast(family IPFamily, min ConnectivityLevel) bool {
+	return c.Level(family).AtLeast(min)
+}
+
+// BestLevel returns the IPFamily with the highest ConnectivityLevel along with
+// that level. When both families are at the same level, IPv4 is returned.
+func (c NLMConnectivity) BestLevel() (IPFamily, ConnectivityLevel) {
+	v4, v6 := c.IPv4Level(), c.IPv6Level()
+	if v6 > v4 {
+		return IPFamilyIPv6, v6
+	}
+	return IPFamilyIPv4, v4
+}
@@ -0,0 +1,144 @@
+package wnlm
+
+import "testing"
+
+func TestConnectivityLevels(t *testing.T) {
+	tests := []struct {
+		name         string
+		connectivity NLMConnectivity
+		ipv4         ConnectivityLevel
+		ipv6         ConnectivityLevel
+		bestFamily   IPFamily
+		bestLevel    ConnectivityLevel
+	}{
+		{
+			name:         "disconnected",
+			connectivity: NLMConnectivityDisconnected,
+			ipv4:         ConnectivityLevelDisconnected,
+			ipv6:         ConnectivityLevelDisconnected,
+			bestFamily:   IPFamilyIPv4,
+			bestLevel:    ConnectivityLevelDisconnected,
+		},
+		{
+			name:         "no traffic",
+			connectivity: NLMConnectivityIPv4NoTraffic | NLMConnectivityIPv6NoTraffic,
+			ipv4:         ConnectivityLevelNoTraffic,
+			ipv6:         ConnectivityLevelNoTraffic,
+			bestFamily:   IPFamilyIPv4,
+			bestLevel:    ConnectivityLevelNoTraffic,
+		},
+		{
+			name:         "highest flag wins",
+			connectivity: NLMConnectivityIPv4NoTraffic | NLMConnectivityIPv4Subnet | NLMConnectivityIPv4Internet,
+			ipv4:         ConnectivityLevelInternet,
+			ipv6:         ConnectivityLevelDisconnected,
+			bestFamily:   IPFamilyIPv4,
+			bestLevel:    ConnectivityLevelInternet,
+		},
+		{
+			name:         "IPv6 better than IPv4",
+			connectivity: NLMConnectivityIPv4Subnet | NLMConnectivityIPv6LocalNetwork,
+			ipv4:         ConnectivityLevelSubnet,
+			ipv6:         ConnectivityLevelLocalNetwork,
+			bestFamily:   IPFamilyIPv6,
+			bestLevel:    ConnectivityLevelLocalNetwork,
+		},
+		{
+			name:         "IPv6 only",
+			connectivity: NLMConnectivityIPv6Internet,
+			ipv4:         ConnectivityLevelDisconnected,
+			ipv6:         ConnectivityLevelInternet,
+			bestFamily:   IPFamilyIPv6,
+			bestLevel:    ConnectivityLevelInternet,
+		},
+		{
+			name:         "tie prefers IPv4",
+			connectivity: NLMConnectivityIPv4Internet | NLMConnectivityIPv6Internet,
+			ipv4:         ConnectivityLevelInternet,
+			ipv6:         ConnectivityLevelInternet,
+			bestFamily:   IPFamilyIPv4,
+			bestLevel:    ConnectivityLevelInternet,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.connectivity.IPv4Level(); got != test.ipv4 {
+				t.Errorf("IPv4Level() = %s, want %s", got, test.ipv4)
+			}
+			if got := test.connectivity.IPv6Level(); got != test.ipv6 {
+				t.Errorf("IPv6Level() = %s, want %s", got, test.ipv6)
+			}
+			if got := test.connectivity.Level(IPFamilyIPv4); got != test.ipv4 {
+				t.Errorf("Level(IPv4) = %s, want %s", got, test.ipv4)
+			}
+			if got := test.connectivity.Level(IPFamilyIPv6); got != test.ipv6 {
+				t.Errorf("Level(IPv6) = %s, want %s", got, test.ipv6)
+			}
+			if family, level := test.connectivity.BestLevel(); family != test.bestFamily || level != test.bestLevel {
+				t.Errorf("BestLevel() = %s, %s, want %s, %s", family, level, test.bestFamily, test.bestLevel)
+			}
+		})
+	}
+}
+
+func TestConnectivityLevelUnknownFamily(t *testing.T) {
+	connectivity := NLMConnectivityIPv4Internet | NLMConnectivityIPv6Internet
+	if got := connectivity.Level(IPFamily(5)); got != ConnectivityLevelDisconnected {
+		t.Errorf("Level(5) = %s, want Disconnected", got)
+	}
+	if connectivity.AtLeast(IPFamily(5), ConnectivityLevelNoTraffic) {
+		t.Errorf("AtLeast(5, NoTraffic) = true, want false")
+	}
+}
+
+func TestConnectivityLevelAtLeast(t *testing.T) {
+	connectivity := NLMConnectivityIPv4Internet | NLMConnectivityIPv6Subnet
+	tests := []struct {
+		family IPFamily
+		min    ConnectivityLevel
+		want   bool
+	}{
+		{IPFamilyIPv4, ConnectivityLevelInternet, true},
+		{IPFamilyIPv4, ConnectivityLevelDisconnected, true},
+		{IPFamilyIPv6, ConnectivityLevelSubnet, true},
+		{IPFamilyIPv6, ConnectivityLevelLocalNetwork, false},
+		{IPFamilyIPv6, ConnectivityLevelInternet, false},
+	}
+	for _, test := range tests {
+		if got := connectivity.AtLeast(test.family, test.min); got != test.want {
+			t.Errorf("AtLeast(%s, %s) = %t, want %t", test.family, test.min, got, test.want)
+		}
+	}
+}
+
+func TestConnectivityLevelCompare(t *testing.T) {
+	levels := []ConnectivityLevel{
+		ConnectivityLevelDisconnected,
+		ConnectivityLevelNoTraffic,
+		ConnectivityLevelSubnet,
+		ConnectivityLevelLocalNetwork,
+		ConnectivityLevelInternet,
+	}
+	for i, a := range levels {
+		for j, b := range levels {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := a.Compare(b); got != want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", a, b, got, want)
+			}
+			if got := a.AtLeast(b); got != (i >= j) {
+				t.Errorf("%s.AtLeast(%s) = %t, want %t", a, b, got, i >= j)
+			}
+		}
+		if a.String() == "" {
+			t.Errorf("ConnectivityLevel(%d).String() is empty", int(a))
+		}
+	}
+	if got := ConnectivityLevel(42).String(); got != "" {
+		t.Errorf("ConnectivityLevel(42).String() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"github.com/adrianosela/wnlm/pkg/dispid"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+	"github.com/go-ole/go-ole"
+)
+
+var (
+	// NOTE(@adrianosela): {DCB00000-570F-4A9B-8D69-199FDBA5723B} is the
+	// well-known Windows Global ID for the INetworkListManager interface.
+	iidINetworkListManager = ole.NewGUID("{DCB00000-570F-4A9B-8D69-199FDBA5723B}")
+
+	// NOTE(@adrianosela): {DCB00002-570F-4A9B-8D69-199FDBA5723B} is the
+	// well-known Windows Global ID for the INetwork interface.
+	iidINetwork = ole.NewGUID("{DCB00002-570F-4A9B-8D69-199FDBA5723B}")
+
+	// NOTE(@adrianosela): {DCB00005-570F-4A9B-8D69-199FDBA5723B} is the
+	// well-known Windows Global ID for the INetworkConnection interface.
+	iidINetworkConnection = ole.NewGUID("{DCB00005-570F-4A9B-8D69-199FDBA5723B}")
+)
+
+var (
+	// iNetworkListManagerMembers are the IDispatch members of INetworkListManager invoked by this module.
+	iNetworkListManagerMembers = []string{
+		"GetNetworkConnections",
+	}
+
+	// iNetworkMembers are the IDispatch members of INetwork invoked by this module.
+	iNetworkMembers = []string{
+		"GetName", "SetName", "GetDescription", "SetDescription",
+		"GetDomainType", "GetNetworkConnections", "IsConnectedToInternet",
+		"IsConnected", "GetConnectivity", "GetCategory", "SetCategory",
+	}
+
+	// iNetworkConnectionMembers are the IDispatch members of INetworkConnection invoked by this module.
+	iNetworkConnectionMembers = []string{
+		"GetNetwork", "IsConnectedToInternet", "IsConnected",
+		"GetConnectivity", "GetDomainType",
+	}
+)
+
+// invoke invokes a member of an object implementing the interface with the given
+// GUID by name, looking up the member's DISPID in the shared dispid.Default cache.
+func invoke(this *ole.IDispatch, iid *ole.GUID, name string, dispatch int16, params ...interface{}) (*ole.VARIANT, error) {
+	id, err := dispid.Default.Lookup(*iid, name, this.GetSingleIDOfName)
+	if err != nil {
+		return nil, hresult.Wrap(err)
+	}
+	res, err := this.Invoke(id, dispatch, params...)
+	if err != nil {
+		return nil, hresult.Wrap(err)
+	}
+	return res, nil
+}
+
+// preResolveDISPIDs resolves the DISPIDs of the given members of an object
+// implementing the interface with the given GUID ahead of their first use.
+func preResolveDISPIDs(this *ole.IDispatch, iid *ole.GUID, members []string) error {
+	if err := dispid.Default.Resolve(*iid, members, this.GetSingleIDOfName); err != nil {
+		return hresult.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package wnlm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// nlmConnectivityFlagToString holds the name of each NLMConnectivity flag.
+var nlmConnectivityFlagToString = map[NLMConnectivity]string{
+	NLMConnectivityIPv4NoTraffic:    "IPv4NoTraffic",
+	NLMConnectivityIPv6NoTraffic:    "IPv6NoTraffic",
+	NLMConnectivityIPv4Subnet:       "IPv4Subnet",
+	NLMConnectivityIPv4LocalNetwork: "IPv4LocalNetwork",
+	NLMConnectivityIPv4Internet:     "IPv4Internet",
+	NLMConnectivityIPv6Subnet:       "IPv6Subnet",
+	NLMConnectivityIPv6LocalNetwork: "IPv6LocalNetwork",
+	NLMConnectivityIPv6Internet:     "IPv6Internet",
+}
+
+// normalizeEnumName returns the canonical form of an enumeration value name used for
+// matching, ignoring case, spaces, dashes and underscores (e.g. "domain-authenticated").
+func normalizeEnumName(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.TrimSpace(name)))
+}
+
+// parseEnum parses the name (or number) of a value of an enumeration.
+// Numbers are only accepted if they are one of the named values.
+func parseEnum[E ~int | ~int32](kind string, s string, names map[E]string) (E, error) {
+	return parseEnumValue(kind, s, names, func(value E) bool {
+		_, ok := names[value]
+		return ok
+	})
+}
+
+// parseFlags parses the name of a flag of a flag enumeration, or the number of any
+// combination of its named flags.
+func parseFlags[E ~int | ~int32](kind string, s string, names map[E]string) (E, error) {
+	var all E
+	for flag := range names {
+		all |= flag
+	}
+	return parseEnumValue(kind, s, names, func(value E) bool { return value&^all == 0 })
+}
+
+// parseEnumValue parses the name of a value of an enumeration, or its number if valid.
+func parseEnumValue[E ~int | ~int32](kind string, s string, names map[E]string, valid func(E) bool) (E, error) {
+	normalized := normalizeEnumName(s)
+	for value, name := range names {
+		if normalizeEnumName(name) == normalized {
+			return value, nil
+		}
+	}
+	if n, err := strconv.ParseInt(strings.TrimSpace(s), 0, 32); err == nil {
+		if !valid(E(n)) {
+			return 0, fmt.Errorf("invalid %s %q: out of range", kind, s)
+		}
+		return E(n), nil
+	}
+	return 0, fmt.Errorf("invalid %s %q", kind, s)
+}
+
+// marshalEnum returns the name of a value of an enumeration, or its number if it has no name.
+func marshalEnum[E ~int | ~int32](value E, names map[E]string) []byte {
+	if name, ok := names[value]; ok {
+		return []byte(name)
+	}
+	return []byte(strconv.Itoa(int(value)))
+}
+
+// ParseNLMNetworkCategory parses an NLMNetworkCategory from its name (e.g. "Private"),
+// ignoring case, spaces, dashes and underscores, or from its number.
+func ParseNLMNetworkCategory(s string) (NLMNetworkCategory, error) {
+	return parseEnum("network category", s, nlmNetworkCategoryToString)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c NLMNetworkCategory) MarshalText() ([]byte, error) {
+	return marshalEnum(c, nlmNetworkCategoryToString), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *NLMNetworkCategory) UnmarshalText(text []byte) (err error) {
+	*c, err = ParseNLMNetworkCategory(string(text))
+	return err
+}
+
+// ParseNLMDomainType parses an NLMDomainType from its name (e.g. "Domain Authenticated"),
+// ignoring case, spaces, dashes and underscores, or from its number.
+func ParseNLMDomainType(s string) (NLMDomainType, error) {
+	return parseEnum("domain type", s, nlmDomainTypeToString)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (t NLMDomainType) MarshalText() ([]byte, error) {
+	return marshalEnum(t, nlmDomainTypeToString), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *NLMDomainType) UnmarshalText(text []byte) (err error) {
+	*t, err = ParseNLMDomainType(string(text))
+	return err
+}
+
+// ParseNLMConnectivity parses an NLMConnectivity from the comma (or pipe) separated
+// names of its flags (e.g. "IPv4Internet, IPv6Subnet"), as returned by its String
+// method, or from its number. "Disconnected" (or an empty string) is no flags.
+func ParseNLMConnectivity(s string) (NLMConnectivity, error) {
+	var connectivity NLMConnectivity
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '|' }) {
+		if normalizeEnumName(name) == "disconnected" {
+			continue
+		}
+		flag, err := parseFlags("connectivity flag", name, nlmConnectivityFlagToString)
+		if err != nil {
+			return 0, err
+		}
+		connectivity |= flag
+	}
+	return connectivity, nil
+}
+
+// Flags returns the names of the flags set on the NLMConnectivity, in sorted order.
+func (c NLMConnectivity) Flags() []string {
+	str := c.String()
+	if c.IsDisconnected() || str == "" {
+		return []string{}
+	}
+	return strings.Split(str, ", ")
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c NLMConnectivity) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *NLMConnectivity) UnmarshalText(text []byte) (err error) {
+	*c, err = ParseNLMConnectivity(string(text))
+	return err
+}
+
+// ParseNLMNetworkPropertyChange parses an NLMNetworkPropertyChange from the comma (or pipe)
+// separated names of its flags (e.g. "Name, Description"), as returned by its String method,
+// or from its number.
+func ParseNLMNetworkPropertyChange(s string) (NLMNetworkPropertyChange, error) {
+	var change NLMNetworkPropertyChange
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '|' }) {
+		flag, err := parseFlags("network property change", name, nlmNetworkPropertyChangeToString)
+		if err != nil {
+			return 0, err
+		}
+		change |= flag
+	}
+	return change, nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c NLMNetworkPropertyChange) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *NLMNetworkPropertyChange) UnmarshalText(text []byte) (err error) {
+	*c, err = ParseNLMNetworkPropertyChange(string(text))
+	return err
+}
+
+// ParseConnectivityLevel parses a ConnectivityLevel from its name (e.g. "LocalNetwork"),
+// ignoring case, spaces, dashes and underscores, or from its number.
+func ParseConnectivityLevel(s string) (ConnectivityLevel, error) {
+	return parseEnum("connectivity level", s, connectivityLevelToString)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (l ConnectivityLevel) MarshalText() ([]byte, error) {
+	return marshalEnum(l, connectivityLevelToString), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (l *ConnectivityLevel) UnmarshalText(text []byte) (err error) {
+	*l, err = ParseConnectivityLevel(string(text))
+	return err
+}
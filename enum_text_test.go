@@ -0,0 +1,52 @@
+package wnlm
+
+import "testing"
+
+func TestParseEnums(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (any, error)
+		input string
+		want  any
+	}{
+		{"category name", func(s string) (any, error) { return ParseNLMNetworkCategory(s) }, "domain-authenticated", NLMNetworkCategoryDomainAuthenticated},
+		{"category number", func(s string) (any, error) { return ParseNLMNetworkCategory(s) }, "1", NLMNetworkCategoryPrivate},
+		{"domain type name", func(s string) (any, error) { return ParseNLMDomainType(s) }, "Domain Authenticated", NLMDomainTypeDomainAuthenticated},
+		{"connectivity names", func(s string) (any, error) { return ParseNLMConnectivity(s) }, "IPv4Internet | ipv6-subnet", NLMConnectivityIPv4Internet | NLMConnectivityIPv6Subnet},
+		{"connectivity number", func(s string) (any, error) { return ParseNLMConnectivity(s) }, "0x440", NLMConnectivityIPv4Internet | NLMConnectivityIPv6Internet},
+		{"disconnected", func(s string) (any, error) { return ParseNLMConnectivity(s) }, "Disconnected", NLMConnectivityDisconnected},
+		{"property change names", func(s string) (any, error) { return ParseNLMNetworkPropertyChange(s) }, "Name, Description", NLMNetworkPropertyChangeName | NLMNetworkPropertyChangeDescription},
+		{"connectivity level", func(s string) (any, error) { return ParseConnectivityLevel(s) }, "local network", ConnectivityLevelLocalNetwork},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.parse(test.input)
+			if err != nil || got != test.want {
+				t.Errorf("parse(%q) = %v, %v, want %v", test.input, got, err, test.want)
+			}
+		})
+	}
+}
+
+func TestParseEnumsOutOfRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) error
+		input string
+	}{
+		{"category", func(s string) (err error) { _, err = ParseNLMNetworkCategory(s); return }, "3"},
+		{"negative category", func(s string) (err error) { _, err = ParseNLMNetworkCategory(s); return }, "-1"},
+		{"domain type", func(s string) (err error) { _, err = ParseNLMDomainType(s); return }, "42"},
+		{"connectivity", func(s string) (err error) { _, err = ParseNLMConnectivity(s); return }, "0x8000"},
+		{"property change", func(s string) (err error) { _, err = ParseNLMNetworkPropertyChange(s); return }, "0x20"},
+		{"connectivity level", func(s string) (err error) { _, err = ParseConnectivityLevel(s); return }, "5"},
+		{"unknown name", func(s string) (err error) { _, err = ParseNLMNetworkCategory(s); return }, "work"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.parse(test.input); err == nil {
+				t.Errorf("parse(%q) succeeded, want an error", test.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package wnlm
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventKind represents the kind of change described by an Event.
+type EventKind int
+
+const (
+	// EventNetworkAdded represents a network being added.
+	EventNetworkAdded = EventKind(0)
+	// EventNetworkDeleted represents a network being deleted.
+	EventNetworkDeleted = EventKind(1)
+	// EventNetworkConnectivityChanged represents a change to the connectivity of a network.
+	EventNetworkConnectivityChanged = EventKind(2)
+	// EventNetworkPropertyChanged represents a change to the properties of a network.
+	EventNetworkPropertyChanged = EventKind(3)
+	// EventConnectionAdded represents a network connection being added.
+	EventConnectionAdded = EventKind(4)
+	// EventConnectionDeleted represents a network connection being deleted.
+	EventConnectionDeleted = EventKind(5)
+	// EventConnectionConnectivityChanged represents a change to the connectivity of a network connection.
+	EventConnectionConnectivityChanged = EventKind(6)
+	// EventConnectionPropertyChanged represents a change to the properties of a network connection.
+	EventConnectionPropertyChanged = EventKind(7)
+)
+
+var eventKindToString = map[EventKind]string{
+	EventNetworkAdded:                  "NetworkAdded",
+	EventNetworkDeleted:                "NetworkDeleted",
+	EventNetworkConnectivityChanged:    "NetworkConnectivityChanged",
+	EventNetworkPropertyChanged:        "NetworkPropertyChanged",
+	EventConnectionAdded:               "ConnectionAdded",
+	EventConnectionDeleted:             "ConnectionDeleted",
+	EventConnectionConnectivityChanged: "ConnectionConnectivityChanged",
+	EventConnectionPropertyChanged:     "ConnectionPropertyChanged",
+}
+
+// String returns the string representation of the EventKind.
+func (k EventKind) String() string {
+	if str, ok := eventKindToString[k]; ok {
+		return str
+	}
+	return ""
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (k EventKind) MarshalText() ([]byte, error) {
+	if str := k.String(); str != "" {
+		return []byte(str), nil
+	}
+	return nil, fmt.Errorf("invalid event kind %d", int(k))
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (k *EventKind) UnmarshalText(text []byte) error {
+	for kind, str := range eventKindToString {
+		if str == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid event kind %q", text)
+}
+
+// IsNetwork returns true if the EventKind is about a network (rather than a network connection).
+func (k EventKind) IsNetwork() bool {
+	return k <= EventNetworkPropertyChanged
+}
+
+// Event is a change to a network or network connection, mirroring the callbacks of the
+// Windows INetworkEvents and INetworkConnectionEvents interfaces.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/netlistmgr/nn-netlistmgr-inetworkevents
+// https://learn.microsoft.com/en-us/windows/win32/api/netlistmgr/nn-netlistmgr-inetworkconnectionevents
+//
+// Fields other than Kind, ID and Time are set when known by the EventSource.
+type Event struct {
+	// Kind is the kind of change.
+	Kind EventKind `json:"kind"`
+	// ID is the GUID of the network (or network connection) which changed.
+	ID GUID `json:"id"`
+	// NetworkID is the GUID of the network (of the network connection) which changed.
+	NetworkID GUID `json:"networkId"`
+	// Name is the name of the network (of the network connection) which changed.
+	Name string `json:"name"`
+	// Connectivity is the new connectivity, for connectivity changes.
+	Connectivity NLMConnectivity `json:"connectivity"`
+	// PreviousConnectivity is the connectivity before the change, for connectivity changes.
+	PreviousConnectivity NLMConnectivity `json:"previousConnectivity"`
+	// Properties are the network properties which changed, for network property changes.
+	Properties NLMNetworkPropertyChange `json:"properties"`
+	// Time is the time at which the change was observed.
+	Time time.Time `json:"time"`
+}
+
+// EventSource delivers Events to subscribers.
+type EventSource interface {
+	// Subscribe calls handle with every Event until the returned function is called.
+	Subscribe(handle func(Event)) (unsubscribe func())
+}
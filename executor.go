@@ -0,0 +1,199 @@
+package wnlm
+
+import (
+	"time"
+
+	"github.com/adrianosela/wnlm/pkg/executor"
+)
+
+// executorNetworkListManager is an INetworkListManager whose calls, and those of
+// every object obtained from it, all run on a single executor.Executor.
+type executorNetworkListManager struct {
+	exec      *executor.Executor
+	nlm       INetworkListManager
+	release   func()
+	onRelease func()
+}
+
+// executorNetwork is an INetwork whose calls all run on a single executor.Executor.
+type executorNetwork struct {
+	exec    *executor.Executor
+	network INetwork
+	release func()
+}
+
+// executorNetworkConnection is an INetworkConnection whose calls all run on a single executor.Executor.
+type executorNetworkConnection struct {
+	exec    *executor.Executor
+	conn    INetworkConnection
+	release func()
+}
+
+// NewExecutorNetworkListManager returns a goroutine-safe INetworkListManager which
+// runs every call to nlm (and to every INetwork, INetworkConnection and enumeration
+// obtained from it) on exec. This is required for INetworkListManager objects that
+// live in a single-threaded COM apartment, which must only be called from the thread
+// that created them. Releasing the returned INetworkListManager does not close exec,
+// but closing exec releases nlm and every object obtained from it which is still
+// outstanding, since they can no longer be released once their apartment is gone.
+func NewExecutorNetworkListManager(exec *executor.Executor, nlm INetworkListManager) INetworkListManager {
+	return &executorNetworkListManager{exec: exec, nlm: nlm, release: exec.Own(nlm.Release)}
+}
+
+// executorNetworkConnections returns an IEnumNetworkConnections whose INetworkConnection
+// objects all run their calls on exec. It must be called by a function running on exec.
+func executorNetworkConnections(exec *executor.Executor, conns IEnumNetworkConnections) IEnumNetworkConnections {
+	wrapped := make([]INetworkConnection, 0, conns.Size())
+	conns.ForEach(func(_ int, conn INetworkConnection) bool {
+		wrapped = append(wrapped, &executorNetworkConnection{exec: exec, conn: conn, release: exec.Own(conn.Release)})
+		return true
+	})
+	return NewNetworkConnectionsFromSlice(wrapped)
+}
+
+// GetNetworkConnections returns all network connections for the system.
+func (m *executorNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	return executor.Call(m.exec, func() (IEnumNetworkConnections, error) {
+		conns, err := m.nlm.GetNetworkConnections()
+		if err != nil {
+			return nil, err
+		}
+		return executorNetworkConnections(m.exec, conns), nil
+	})
+}
+
+// Release releases the INetworkListManager object.
+func (m *executorNetworkListManager) Release() {
+	m.release()
+	if m.onRelease != nil {
+		m.onRelease()
+	}
+}
+
+// GetName gets the name of this network.
+func (n *executorNetwork) GetName() (string, error) {
+	return executor.Call(n.exec, n.network.GetName)
+}
+
+// SetName sets the name of this network.
+func (n *executorNetwork) SetName(name string) error {
+	return n.exec.Do(func() error { return n.network.SetName(name) })
+}
+
+// GetDescription gets the description/alias of this network.
+func (n *executorNetwork) GetDescription() (string, error) {
+	return executor.Call(n.exec, n.network.GetDescription)
+}
+
+// SetDescription sets the description/alias of this network.
+func (n *executorNetwork) SetDescription(descr string) error {
+	return n.exec.Do(func() error { return n.network.SetDescription(descr) })
+}
+
+// GetNetworkId returns the GUID of this network.
+func (n *executorNetwork) GetNetworkId() (GUID, error) {
+	return executor.Call(n.exec, n.network.GetNetworkId)
+}
+
+// GetDomainType gets the domain type of this network.
+func (n *executorNetwork) GetDomainType() (NLMDomainType, error) {
+	return executor.Call(n.exec, n.network.GetDomainType)
+}
+
+// GetNetworkConnections returns the network connections for this network.
+func (n *executorNetwork) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	return executor.Call(n.exec, func() (IEnumNetworkConnections, error) {
+		conns, err := n.network.GetNetworkConnections()
+		if err != nil {
+			return nil, err
+		}
+		return executorNetworkConnections(n.exec, conns), nil
+	})
+}
+
+// GetTimeCreatedAndConnected gets the timestamps of this network being created and connected.
+func (n *executorNetwork) GetTimeCreatedAndConnected() (time.Time, time.Time, error) {
+	var created, connected time.Time
+	err := n.exec.Do(func() error {
+		var err error
+		created, connected, err = n.network.GetTimeCreatedAndConnected()
+		return err
+	})
+	return created, connected, err
+}
+
+// IsConnectedToInternet returns whether the network is connected to the Internet.
+func (n *executorNetwork) IsConnectedToInternet() (bool, error) {
+	return executor.Call(n.exec, n.network.IsConnectedToInternet)
+}
+
+// IsConnected returns whether the network is connected.
+func (n *executorNetwork) IsConnected() (bool, error) {
+	return executor.Call(n.exec, n.network.IsConnected)
+}
+
+// GetConnectivity gets the connectivity of this network.
+func (n *executorNetwork) GetConnectivity() (NLMConnectivity, error) {
+	return executor.Call(n.exec, n.network.GetConnectivity)
+}
+
+// GetCategory gets the category of this network.
+func (n *executorNetwork) GetCategory() (NLMNetworkCategory, error) {
+	return executor.Call(n.exec, n.network.GetCategory)
+}
+
+// SetCategory sets the category of this network.
+func (n *executorNetwork) SetCategory(category NLMNetworkCategory) error {
+	return n.exec.Do(func() error { return n.network.SetCategory(category) })
+}
+
+// Release releases the INetwork object.
+func (n *executorNetwork) Release() {
+	n.release()
+}
+
+// GetNetwork returns the INetwork for a network connection.
+func (nc *executorNetworkConnection) GetNetwork() (INetwork, error) {
+	return executor.Call(nc.exec, func() (INetwork, error) {
+		network, err := nc.conn.GetNetwork()
+		if err != nil {
+			return nil, err
+		}
+		return &executorNetwork{exec: nc.exec, network: network, release: nc.exec.Own(network.Release)}, nil
+	})
+}
+
+// IsConnectedToInternet returns whether the network connection is connected to the Internet.
+func (nc *executorNetworkConnection) IsConnectedToInternet() (bool, error) {
+	return executor.Call(nc.exec, nc.conn.IsConnectedToInternet)
+}
+
+// IsConnected returns whether the network connection is connected.
+func (nc *executorNetworkConnection) IsConnected() (bool, error) {
+	return executor.Call(nc.exec, nc.conn.IsConnected)
+}
+
+// GetConnectivity gets the connectivity of this network connection.
+func (nc *executorNetworkConnection) GetConnectivity() (NLMConnectivity, error) {
+	return executor.Call(nc.exec, nc.conn.GetConnectivity)
+}
+
+// GetConnectionId returns the connection GUID for a network connection.
+func (nc *executorNetworkConnection) GetConnectionId() (GUID, error) {
+	return executor.Call(nc.exec, nc.conn.GetConnectionId)
+}
+
+// GetAdapterId returns the adapter GUID for a network connection.
+func (nc *executorNetworkConnection) GetAdapterId() (GUID, error) {
+	return executor.Call(nc.exec, nc.conn.GetAdapterId)
+}
+
+// GetDomainType gets the domain type of this network connection.
+func (nc *executorNetworkConnection) GetDomainType() (NLMDomainType, error) {
+	return executor.Call(nc.exec, nc.conn.GetDomainType)
+}
+
+// Release releases the INetworkConnection object.
+func (nc *executorNetworkConnection) Release() {
+	nc.release()
+}
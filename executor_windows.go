@@ -0,0 +1,42 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"fmt"
+
+	"github.com/adrianosela/wnlm/pkg/executor"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+	"github.com/go-ole/go-ole"
+)
+
+// coInitializeEx initializes COM on the calling thread with the given concurrency
+// model, treating S_FALSE (COM already initialized on the thread) as success.
+func coInitializeEx(coinit uint32) error {
+	if err := ole.CoInitializeEx(0, coinit); err != nil {
+		if hr, ok := hresult.FromError(err); ok && hr.Succeeded() {
+			return nil
+		}
+		return hresult.Wrap(err)
+	}
+	return nil
+}
+
+// NewApartmentNetworkListManager initializes a new Windows NetworkListManager API client
+// inside its own single-threaded COM apartment, which is owned by a dedicated OS thread.
+//
+// The returned INetworkListManager, and every object obtained from it, is goroutine-safe:
+// all calls are marshalled onto the apartment's thread. Releasing the INetworkListManager
+// uninitializes COM on that thread, after which objects obtained from it are unusable.
+func NewApartmentNetworkListManager(opts ...ManagerOption) (INetworkListManager, error) {
+	exec, err := executor.New(func() error { return coInitializeEx(ole.COINIT_APARTMENTTHREADED) }, ole.CoUninitialize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize COM apartment: %w", err)
+	}
+	nlm, err := executor.Call(exec, func() (INetworkListManager, error) { return NewNetworkListManager(opts...) })
+	if err != nil {
+		exec.Close()
+		return nil, err
+	}
+	return &executorNetworkListManager{exec: exec, nlm: nlm, release: exec.Own(nlm.Release), onRelease: exec.Close}, nil
+}
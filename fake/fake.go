@@ -0,0 +1,302 @@
+// Package fake provides an in-memory implementation of the wnlm interfaces,
+// for exercising code written against them on any platform.
+package fake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// Network is the state of a fake network.
+type Network struct {
+	ID           wnlm.GUID               `json:"id"`
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description"`
+	DomainType   wnlm.NLMDomainType      `json:"domainType"`
+	Category     wnlm.NLMNetworkCategory `json:"category"`
+	Connectivity wnlm.NLMConnectivity    `json:"connectivity"`
+	Created      time.Time               `json:"created"`
+	Connected    time.Time               `json:"connected"`
+}
+
+// Connection is the state of a fake network connection.
+type Connection struct {
+	ID           wnlm.GUID            `json:"id"`
+	AdapterID    wnlm.GUID            `json:"adapterId"`
+	NetworkID    wnlm.GUID            `json:"networkId"`
+	DomainType   wnlm.NLMDomainType   `json:"domainType"`
+	Connectivity wnlm.NLMConnectivity `json:"connectivity"`
+}
+
+// Fault is called before every call made to a fake object with the name of the
+// interface (e.g. "INetwork") and method (e.g. "GetName") being called. A non-nil
+// error fails the call with that error.
+type Fault func(iface, method string) error
+
+// NetworkListManager is a fake wnlm.INetworkListManager backed by in-memory
+// state, which can be changed at any time and is seen live by every object
+// obtained from it. It keeps track of the references it hands out, much like a
+// COM object would, so that tests can check for leaks and double releases.
+type NetworkListManager struct {
+	mu             sync.Mutex
+	networks       []*Network
+	connections    []*Connection
+	fault          Fault
+	outstanding    int
+	doubleReleases int
+	released       bool
+}
+
+var _ wnlm.INetworkListManager = (*NetworkListManager)(nil)
+
+// New returns a new, empty, fake NetworkListManager. The returned object
+// itself counts as an outstanding reference until it is released.
+func New() *NetworkListManager {
+	return &NetworkListManager{outstanding: 1}
+}
+
+// SetFault sets the Fault used to inject errors into calls, or clears it if nil.
+func (m *NetworkListManager) SetFault(fault Fault) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fault = fault
+}
+
+// AddNetwork adds (or replaces, by ID) a network.
+func (m *NetworkListManager) AddNetwork(network Network) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.networks {
+		if existing.ID == network.ID {
+			m.networks[i] = &network
+			return
+		}
+	}
+	m.networks = append(m.networks, &network)
+}
+
+// UpdateNetwork applies update to the network with the given ID, returning
+// false if there is no such network.
+func (m *NetworkListManager) UpdateNetwork(id wnlm.GUID, update func(*Network)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	network := m.network(id)
+	if network == nil {
+		return false
+	}
+	update(network)
+	return true
+}
+
+// RemoveNetwork removes the network with the given ID, along with its connections.
+func (m *NetworkListManager) RemoveNetwork(id wnlm.GUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	networks := m.networks[:0]
+	for _, network := range m.networks {
+		if network.ID != id {
+			networks = append(networks, network)
+		}
+	}
+	m.networks = networks
+	connections := m.connections[:0]
+	for _, conn := range m.connections {
+		if conn.NetworkID != id {
+			connections = append(connections, conn)
+		}
+	}
+	m.connections = connections
+}
+
+// AddConnection adds (or replaces, by ID) a network connection.
+func (m *NetworkListManager) AddConnection(conn Connection) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.connections {
+		if existing.ID == conn.ID {
+			m.connections[i] = &conn
+			return
+		}
+	}
+	m.connections = append(m.connections, &conn)
+}
+
+// UpdateConnection applies update to the network connection with the given
+// ID, returning false if there is no such connection.
+func (m *NetworkListManager) UpdateConnection(id wnlm.GUID, update func(*Connection)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	conn := m.connection(id)
+	if conn == nil {
+		return false
+	}
+	update(conn)
+	return true
+}
+
+// RemoveConnection removes the network connection with the given ID.
+func (m *NetworkListManager) RemoveConnection(id wnlm.GUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	connections := m.connections[:0]
+	for _, conn := range m.connections {
+		if conn.ID != id {
+			connections = append(connections, conn)
+		}
+	}
+	m.connections = connections
+}
+
+// Network returns a copy of the state of the network with the given ID.
+func (m *NetworkListManager) Network(id wnlm.GUID) (Network, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if network := m.network(id); network != nil {
+		return *network, true
+	}
+	return Network{}, false
+}
+
+// Connection returns a copy of the state of the network connection with the given ID.
+func (m *NetworkListManager) Connection(id wnlm.GUID) (Connection, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if conn := m.connection(id); conn != nil {
+		return *conn, true
+	}
+	return Connection{}, false
+}
+
+// Outstanding returns the number of references handed out (including the
+// NetworkListManager itself) which have not been released yet.
+func (m *NetworkListManager) Outstanding() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.outstanding
+}
+
+// DoubleReleases returns the number of times an already released object was released again.
+func (m *NetworkListManager) DoubleReleases() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doubleReleases
+}
+
+// network returns the network with the given ID, or nil. m.mu must be held.
+func (m *NetworkListManager) network(id wnlm.GUID) *Network {
+	for _, network := range m.networks {
+		if network.ID == id {
+			return network
+		}
+	}
+	return nil
+}
+
+// connection returns the connection with the given ID, or nil. m.mu must be held.
+func (m *NetworkListManager) connection(id wnlm.GUID) *Connection {
+	for _, conn := range m.connections {
+		if conn.ID == id {
+			return conn
+		}
+	}
+	return nil
+}
+
+// call checks for injected faults, then runs fn with m.mu held.
+func (m *NetworkListManager) call(iface, method string, fn func() error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fault != nil {
+		if err := m.fault(iface, method); err != nil {
+			return err
+		}
+	}
+	return fn()
+}
+
+// acquire records a new outstanding reference. m.mu must be held.
+func (m *NetworkListManager) acquire() *ref {
+	m.outstanding++
+	return &ref{manager: m}
+}
+
+// ref is a reference handed out by a NetworkListManager.
+type ref struct {
+	manager  *NetworkListManager
+	released bool
+}
+
+// release releases the reference, recording double releases.
+func (r *ref) release() {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if r.released {
+		r.manager.doubleReleases++
+		return
+	}
+	r.released = true
+	r.manager.outstanding--
+}
+
+// newNetwork returns a new reference to the network with the given ID. m.mu must be held.
+func newNetwork(m *NetworkListManager, id wnlm.GUID) *network {
+	return &network{ref: m.acquire(), id: id}
+}
+
+// newNetworkConnection returns a new reference to the network connection with the given ID. m.mu must be held.
+func newNetworkConnection(m *NetworkListManager, id wnlm.GUID) *networkConnection {
+	return &networkConnection{ref: m.acquire(), id: id}
+}
+
+// enumConnections returns an enumeration of (new references to) the network
+// connections matching the given filter. m.mu must be held.
+func (m *NetworkListManager) enumConnections(match func(*Connection) bool) wnlm.IEnumNetworkConnections {
+	conns := []wnlm.INetworkConnection{}
+	for _, conn := range m.connections {
+		if match(conn) {
+			conns = append(conns, newNetworkConnection(m, conn.ID))
+		}
+	}
+	return wnlm.NewNetworkConnectionsFromSlice(conns)
+}
+
+// GetNetworkConnections returns all network connections.
+func (m *NetworkListManager) GetNetworkConnections() (wnlm.IEnumNetworkConnections, error) {
+	var conns wnlm.IEnumNetworkConnections
+	err := m.call("INetworkListManager", "GetNetworkConnections", func() error {
+		conns = m.enumConnections(func(*Connection) bool { return true })
+		return nil
+	})
+	return conns, err
+}
+
+// Release releases the NetworkListManager.
+func (m *NetworkListManager) Release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.released {
+		m.doubleReleases++
+		return
+	}
+	m.released = true
+	m.outstanding--
+}
+
+// errNotFound is returned by objects whose state has been removed.
+func errNotFound(kind string, id wnlm.GUID) error {
+	return fmt.Errorf("%s %s no longer exists: %w", kind, id, hresult.E_NOT_FOUND)
+}
+
+// isConnected returns true if the connectivity is any other than disconnected.
+func isConnected(connectivity wnlm.NLMConnectivity) bool {
+	return !connectivity.IsDisconnected()
+}
+
+// isConnectedToInternet returns true if the connectivity includes internet over IPv4 or IPv6.
+func isConnectedToInternet(connectivity wnlm.NLMConnectivity) bool {
+	return connectivity.IsIPv4Internet() || connectivity.IsIPv6Internet()
+}
@@ -0,0 +1,149 @@
+package fake
+
+import (
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// network is a fake wnlm.INetwork, a reference to a Network held by a NetworkListManager.
+type network struct {
+	*ref
+	id wnlm.GUID
+}
+
+var _ wnlm.INetwork = (*network)(nil)
+
+// get runs fn against the current state of the network.
+func (n *network) get(method string, fn func(*Network) error) error {
+	return n.manager.call("INetwork", method, func() error {
+		state := n.manager.network(n.id)
+		if state == nil {
+			return errNotFound("network", n.id)
+		}
+		return fn(state)
+	})
+}
+
+// GetName returns the name of the network.
+func (n *network) GetName() (name string, err error) {
+	err = n.get("GetName", func(state *Network) error {
+		name = state.Name
+		return nil
+	})
+	return name, err
+}
+
+// SetName sets the name of the network.
+func (n *network) SetName(name string) error {
+	return n.get("SetName", func(state *Network) error {
+		state.Name = name
+		return nil
+	})
+}
+
+// GetDescription returns the description of the network.
+func (n *network) GetDescription() (description string, err error) {
+	err = n.get("GetDescription", func(state *Network) error {
+		description = state.Description
+		return nil
+	})
+	return description, err
+}
+
+// SetDescription sets the description of the network.
+func (n *network) SetDescription(description string) error {
+	return n.get("SetDescription", func(state *Network) error {
+		state.Description = description
+		return nil
+	})
+}
+
+// GetNetworkId returns the ID of the network.
+func (n *network) GetNetworkId() (id wnlm.GUID, err error) {
+	err = n.get("GetNetworkId", func(state *Network) error {
+		id = state.ID
+		return nil
+	})
+	return id, err
+}
+
+// GetDomainType returns the domain type of the network.
+func (n *network) GetDomainType() (domainType wnlm.NLMDomainType, err error) {
+	err = n.get("GetDomainType", func(state *Network) error {
+		domainType = state.DomainType
+		return nil
+	})
+	return domainType, err
+}
+
+// GetNetworkConnections returns the network connections of the network.
+func (n *network) GetNetworkConnections() (conns wnlm.IEnumNetworkConnections, err error) {
+	err = n.get("GetNetworkConnections", func(state *Network) error {
+		conns = n.manager.enumConnections(func(conn *Connection) bool { return conn.NetworkID == state.ID })
+		return nil
+	})
+	return conns, err
+}
+
+// GetTimeCreatedAndConnected returns the times at which the network was created and last connected.
+func (n *network) GetTimeCreatedAndConnected() (created time.Time, connected time.Time, err error) {
+	err = n.get("GetTimeCreatedAndConnected", func(state *Network) error {
+		created, connected = state.Created, state.Connected
+		return nil
+	})
+	return created, connected, err
+}
+
+// IsConnectedToInternet returns true if the network has internet connectivity.
+func (n *network) IsConnectedToInternet() (connected bool, err error) {
+	err = n.get("IsConnectedToInternet", func(state *Network) error {
+		connected = isConnectedToInternet(state.Connectivity)
+		return nil
+	})
+	return connected, err
+}
+
+// IsConnected returns true if the network has any connectivity.
+func (n *network) IsConnected() (connected bool, err error) {
+	err = n.get("IsConnected", func(state *Network) error {
+		connected = isConnected(state.Connectivity)
+		return nil
+	})
+	return connected, err
+}
+
+// GetConnectivity returns the connectivity of the network.
+func (n *network) GetConnectivity() (connectivity wnlm.NLMConnectivity, err error) {
+	err = n.get("GetConnectivity", func(state *Network) error {
+		connectivity = state.Connectivity
+		return nil
+	})
+	return connectivity, err
+}
+
+// GetCategory returns the category of the network.
+func (n *network) GetCategory() (category wnlm.NLMNetworkCategory, err error) {
+	err = n.get("GetCategory", func(state *Network) error {
+		category = state.Category
+		return nil
+	})
+	return category, err
+}
+
+// SetCategory sets the category of the network.
+func (n *network) SetCategory(category wnlm.NLMNetworkCategory) error {
+	return n.get("SetCategory", func(state *Network) error {
+		if category.String() == "" {
+			return hresult.E_INVALIDARG
+		}
+		state.Category = category
+		return nil
+	})
+}
+
+// Release releases the network.
+func (n *network) Release() {
+	n.release()
+}
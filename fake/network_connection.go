@@ -0,0 +1,94 @@
+package fake
+
+import "github.com/adrianosela/wnlm"
+
+// networkConnection is a fake wnlm.INetworkConnection, a reference to a
+// Connection held by a NetworkListManager.
+type networkConnection struct {
+	*ref
+	id wnlm.GUID
+}
+
+var _ wnlm.INetworkConnection = (*networkConnection)(nil)
+
+// get runs fn against the current state of the network connection.
+func (nc *networkConnection) get(method string, fn func(*Connection) error) error {
+	return nc.manager.call("INetworkConnection", method, func() error {
+		state := nc.manager.connection(nc.id)
+		if state == nil {
+			return errNotFound("network connection", nc.id)
+		}
+		return fn(state)
+	})
+}
+
+// GetNetwork returns the network of the network connection.
+func (nc *networkConnection) GetNetwork() (network wnlm.INetwork, err error) {
+	err = nc.get("GetNetwork", func(state *Connection) error {
+		if nc.manager.network(state.NetworkID) == nil {
+			return errNotFound("network", state.NetworkID)
+		}
+		network = newNetwork(nc.manager, state.NetworkID)
+		return nil
+	})
+	return network, err
+}
+
+// IsConnectedToInternet returns true if the network connection has internet connectivity.
+func (nc *networkConnection) IsConnectedToInternet() (connected bool, err error) {
+	err = nc.get("IsConnectedToInternet", func(state *Connection) error {
+		connected = isConnectedToInternet(state.Connectivity)
+		return nil
+	})
+	return connected, err
+}
+
+// IsConnected returns true if the network connection has any connectivity.
+func (nc *networkConnection) IsConnected() (connected bool, err error) {
+	err = nc.get("IsConnected", func(state *Connection) error {
+		connected = isConnected(state.Connectivity)
+		return nil
+	})
+	return connected, err
+}
+
+// GetConnectivity returns the connectivity of the network connection.
+func (nc *networkConnection) GetConnectivity() (connectivity wnlm.NLMConnectivity, err error) {
+	err = nc.get("GetConnectivity", func(state *Connection) error {
+		connectivity = state.Connectivity
+		return nil
+	})
+	return connectivity, err
+}
+
+// GetConnectionId returns the ID of the network connection.
+func (nc *networkConnection) GetConnectionId() (id wnlm.GUID, err error) {
+	err = nc.get("GetConnectionId", func(state *Connection) error {
+		id = state.ID
+		return nil
+	})
+	return id, err
+}
+
+// GetAdapterId returns the ID of the network adapter of the network connection.
+func (nc *networkConnection) GetAdapterId() (id wnlm.GUID, err error) {
+	err = nc.get("GetAdapterId", func(state *Connection) error {
+		id = state.AdapterID
+		return nil
+	})
+	return id, err
+}
+
+// GetDomainType returns the domain type of the network connection.
+func (nc *networkConnection) GetDomainType() (domainType wnlm.NLMDomainType, err error) {
+	err = nc.get("GetDomainType", func(state *Connection) error {
+		domainType = state.DomainType
+		return nil
+	})
+	return domainType, err
+}
+
+// Release releases the network connection.
+func (nc *networkConnection) Release() {
+	nc.release()
+}
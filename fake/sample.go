@@ -0,0 +1,70 @@
+package fake
+
+import (
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// The GUIDs of the networks, network connections and adapter of SampleState.
+var (
+	SampleHomeID     = wnlm.MustParseGUID("{11111111-1111-1111-1111-111111111111}")
+	SampleOfficeID   = wnlm.MustParseGUID("{22222222-2222-2222-2222-222222222222}")
+	SampleWiFiID     = wnlm.MustParseGUID("{AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA}")
+	SampleEthernetID = wnlm.MustParseGUID("{BBBBBBBB-BBBB-BBBB-BBBB-BBBBBBBBBBBB}")
+	SampleAdapterID  = wnlm.MustParseGUID("{CCCCCCCC-CCCC-CCCC-CCCC-CCCCCCCCCCCC}")
+)
+
+// SampleState returns a State with a connected private network ("Home") with one
+// connection ("WiFi") and a disconnected public one ("Office") with another
+// ("Ethernet"), both on the same adapter.
+func SampleState() State {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return State{
+		Networks: []Network{
+			{
+				ID:           SampleHomeID,
+				Name:         "Home",
+				Description:  "Home network",
+				DomainType:   wnlm.NLMDomainTypeNonDomainNetwork,
+				Category:     wnlm.NLMNetworkCategoryPrivate,
+				Connectivity: wnlm.NLMConnectivityIPv4Internet | wnlm.NLMConnectivityIPv6LocalNetwork,
+				Created:      created,
+				Connected:    created.Add(time.Hour),
+			},
+			{
+				ID:           SampleOfficeID,
+				Name:         "Office",
+				Description:  "Office network",
+				DomainType:   wnlm.NLMDomainTypeDomainAuthenticated,
+				Category:     wnlm.NLMNetworkCategoryPublic,
+				Connectivity: wnlm.NLMConnectivityDisconnected,
+				Created:      created.Add(-24 * time.Hour),
+				Connected:    created.Add(-time.Hour),
+			},
+		},
+		Connections: []Connection{
+			{
+				ID:           SampleWiFiID,
+				AdapterID:    SampleAdapterID,
+				NetworkID:    SampleHomeID,
+				DomainType:   wnlm.NLMDomainTypeNonDomainNetwork,
+				Connectivity: wnlm.NLMConnectivityIPv4Internet | wnlm.NLMConnectivityIPv6LocalNetwork,
+			},
+			{
+				ID:           SampleEthernetID,
+				AdapterID:    SampleAdapterID,
+				NetworkID:    SampleOfficeID,
+				DomainType:   wnlm.NLMDomainTypeDomainAuthenticated,
+				Connectivity: wnlm.NLMConnectivityDisconnected,
+			},
+		},
+	}
+}
+
+// NewSample returns a new fake NetworkListManager holding SampleState.
+func NewSample() *NetworkListManager {
+	m := New()
+	m.SetState(SampleState())
+	return m
+}
@@ -0,0 +1,73 @@
+package fake
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// Step is a scripted change to the state of a NetworkListManager. Every
+// non-nil change of a Step is applied, in the order of its fields.
+type Step struct {
+	// After is how long to wait, after the previous Step, before applying this one.
+	After Duration `json:"after"`
+	// SetNetwork adds (or replaces, by ID) a network.
+	SetNetwork *Network `json:"setNetwork,omitempty"`
+	// RemoveNetwork removes a network, along with its connections.
+	RemoveNetwork *wnlm.GUID `json:"removeNetwork,omitempty"`
+	// SetConnection adds (or replaces, by ID) a network connection.
+	SetConnection *Connection `json:"setConnection,omitempty"`
+	// RemoveConnection removes a network connection.
+	RemoveConnection *wnlm.GUID `json:"removeConnection,omitempty"`
+}
+
+// Duration is a time.Duration which is (un)marshalled as text, e.g. "1.5s".
+type Duration time.Duration
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Apply applies a Step right away, regardless of its After.
+func (m *NetworkListManager) Apply(step Step) {
+	if step.SetNetwork != nil {
+		m.AddNetwork(*step.SetNetwork)
+	}
+	if step.RemoveNetwork != nil {
+		m.RemoveNetwork(*step.RemoveNetwork)
+	}
+	if step.SetConnection != nil {
+		m.AddConnection(*step.SetConnection)
+	}
+	if step.RemoveConnection != nil {
+		m.RemoveConnection(*step.RemoveConnection)
+	}
+}
+
+// Play applies each Step of a script in turn, once it is due, until
+// the script ends (returning nil) or ctx is done (returning its error).
+func (m *NetworkListManager) Play(ctx context.Context, script []Step) error {
+	for _, step := range script {
+		timer := time.NewTimer(time.Duration(step.After))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		m.Apply(step)
+	}
+	return nil
+}
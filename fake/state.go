@@ -0,0 +1,72 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// State is the state of every network and network connection of a NetworkListManager.
+type State struct {
+	Networks    []Network    `json:"networks"`
+	Connections []Connection `json:"connections"`
+}
+
+// SetState replaces the state of the NetworkListManager.
+func (m *NetworkListManager) SetState(state State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.networks = make([]*Network, 0, len(state.Networks))
+	for _, network := range state.Networks {
+		m.networks = append(m.networks, &network)
+	}
+	m.connections = make([]*Connection, 0, len(state.Connections))
+	for _, conn := range state.Connections {
+		m.connections = append(m.connections, &conn)
+	}
+}
+
+// State returns a copy of the state of the NetworkListManager.
+func (m *NetworkListManager) State() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state := State{Networks: []Network{}, Connections: []Connection{}}
+	for _, network := range m.networks {
+		state.Networks = append(state.Networks, *network)
+	}
+	for _, conn := range m.connections {
+		state.Connections = append(state.Connections, *conn)
+	}
+	return state
+}
+
+// Fixture is an initial State, and a script of changes to it (see Play).
+type Fixture struct {
+	State
+	Script []Step `json:"script"`
+}
+
+// ReadFixture reads a Fixture held, as JSON, in the named file.
+func ReadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake state file: %w", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fake state file %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// LoadFile returns a new NetworkListManager with the State held, as JSON, in the
+// named file. The file may also hold a Fixture, whose script is ignored.
+func LoadFile(path string) (*NetworkListManager, error) {
+	fixture, err := ReadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	m := New()
+	m.SetState(fixture.State)
+	return m, nil
+}
@@ -0,0 +1,8 @@
+package wnlm_test
+
+import "github.com/adrianosela/wnlm"
+
+// factory returns a ManagerFactory which returns m.
+func factory(m wnlm.INetworkListManager) wnlm.ManagerFactory {
+	return func(...wnlm.ManagerOption) (wnlm.INetworkListManager, error) { return m, nil }
+}
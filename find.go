@@ -0,0 +1,71 @@
+package wnlm
+
+import (
+	"fmt"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// FindNetworkConnection returns the network connection with the given GUID, failing
+// with hresult.E_NOT_FOUND if there is none. The caller must release it, which releases
+// the enumeration it was found in.
+func FindNetworkConnection(nlm INetworkListManager, id GUID) (INetworkConnection, error) {
+	conns, err := nlm.GetNetworkConnections()
+	if err != nil {
+		return nil, err
+	}
+	var found INetworkConnection
+	conns.ForEach(func(_ int, conn INetworkConnection) bool {
+		if connID, err := conn.GetConnectionId(); err == nil && connID == id {
+			found = conn
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		conns.Release()
+		return nil, fmt.Errorf("network connection %s not found: %w", id, hresult.E_NOT_FOUND)
+	}
+	return &enumeratedNetworkConnection{INetworkConnection: found, conns: conns}, nil
+}
+
+// enumeratedNetworkConnection is a network connection released along with the
+// enumeration it was obtained from, since releasing the enumeration releases it.
+type enumeratedNetworkConnection struct {
+	INetworkConnection
+	conns IEnumNetworkConnections
+}
+
+// Release releases the enumeration of the network connection, and with it the network connection.
+func (nc *enumeratedNetworkConnection) Release() {
+	nc.conns.Release()
+}
+
+// FindNetwork returns the network with the given GUID, found through the network
+// connections of nlm, failing with hresult.E_NOT_FOUND if there is none. The caller
+// must release it.
+func FindNetwork(nlm INetworkListManager, id GUID) (INetwork, error) {
+	conns, err := nlm.GetNetworkConnections()
+	if err != nil {
+		return nil, err
+	}
+	defer conns.Release()
+
+	var found INetwork
+	conns.ForEach(func(_ int, conn INetworkConnection) bool {
+		network, err := conn.GetNetwork()
+		if err != nil {
+			return true
+		}
+		if networkID, err := network.GetNetworkId(); err == nil && networkID == id {
+			found = network
+			return false
+		}
+		network.Release()
+		return true
+	})
+	if found == nil {
+		return nil, fmt.Errorf("network %s not found: %w", id, hresult.E_NOT_FOUND)
+	}
+	return found, nil
+}
@@ -0,0 +1,37 @@
+package wnlm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+func TestFindNetworkConnection(t *testing.T) {
+	tracker := wnlm.NewTracker()
+	m := fake.NewSample()
+	nlm := tracker.TrackNetworkListManager(m)
+
+	conn, err := wnlm.FindNetworkConnection(nlm, fake.SampleEthernetID)
+	if err != nil {
+		t.Fatalf("FindNetworkConnection failed: %v", err)
+	}
+	if id, err := conn.GetAdapterId(); err != nil || id != fake.SampleAdapterID {
+		t.Errorf("GetAdapterId() = %v, %v, want %v", id, err, fake.SampleAdapterID)
+	}
+	conn.Release()
+
+	if _, err := wnlm.FindNetworkConnection(nlm, fake.SampleHomeID); !errors.Is(err, hresult.E_NOT_FOUND) {
+		t.Errorf("FindNetworkConnection() of a network = %v, want E_NOT_FOUND", err)
+	}
+	nlm.Release()
+
+	if err := tracker.Check(); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("fake has %d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+}
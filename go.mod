@@ -4,4 +4,4 @@ go 1.23.6
 
 require github.com/go-ole/go-ole v1.3.0
 
-require golang.org/x/sys v0.31.0 // indirect
+require golang.org/x/sys v0.31.0
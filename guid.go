@@ -0,0 +1,125 @@
+package wnlm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-ole/go-ole"
+)
+
+// GUID represents a Windows Globally Unique Identifier (GUID).
+//
+// Its memory layout matches that of the Windows GUID structure (and therefore
+// that of both windows.GUID and ole.GUID), and it is comparable, so it can be
+// used as a map key.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/guiddef/ns-guiddef-guid
+type GUID struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+// ParseGUID parses a GUID from its string representation, with or without
+// surrounding braces e.g. "{DCB00000-570F-4A9B-8D69-199FDBA5723B}". Hexadecimal
+// digits are accepted in either case.
+func ParseGUID(s string) (GUID, error) {
+	b := []byte(s)
+	if len(b) == 38 {
+		if b[0] != '{' || b[37] != '}' {
+			return GUID{}, fmt.Errorf("invalid GUID %q: expected surrounding braces", s)
+		}
+		b = b[1:37]
+	}
+	if len(b) != 36 {
+		return GUID{}, fmt.Errorf("invalid GUID %q: unexpected length %d", s, len(s))
+	}
+	if b[8] != '-' || b[13] != '-' || b[18] != '-' || b[23] != '-' {
+		return GUID{}, fmt.Errorf("invalid GUID %q: misplaced separators", s)
+	}
+
+	var raw [16]byte
+	for i, part := range [][2]int{{0, 8}, {9, 13}, {14, 18}, {19, 23}, {24, 36}} {
+		offset := [...]int{0, 4, 6, 8, 10}[i]
+		if _, err := hex.Decode(raw[offset:], b[part[0]:part[1]]); err != nil {
+			return GUID{}, fmt.Errorf("invalid GUID %q: %w", s, err)
+		}
+	}
+
+	guid := GUID{
+		Data1: uint32(raw[0])<<24 | uint32(raw[1])<<16 | uint32(raw[2])<<8 | uint32(raw[3]),
+		Data2: uint16(raw[4])<<8 | uint16(raw[5]),
+		Data3: uint16(raw[6])<<8 | uint16(raw[7]),
+	}
+	copy(guid.Data4[:], raw[8:])
+	return guid, nil
+}
+
+// MustParseGUID is like ParseGUID but panics if the string cannot be parsed.
+func MustParseGUID(s string) GUID {
+	guid, err := ParseGUID(s)
+	if err != nil {
+		panic(err)
+	}
+	return guid
+}
+
+// bytes returns the GUID's 16 bytes in the order of its string representation.
+func (g GUID) bytes() [16]byte {
+	var b [16]byte
+	b[0], b[1], b[2], b[3] = byte(g.Data1>>24), byte(g.Data1>>16), byte(g.Data1>>8), byte(g.Data1)
+	b[4], b[5] = byte(g.Data2>>8), byte(g.Data2)
+	b[6], b[7] = byte(g.Data3>>8), byte(g.Data3)
+	copy(b[8:], g.Data4[:])
+	return b
+}
+
+// String returns the canonical string representation of the GUID, which is
+// upper case and surrounded by braces e.g. "{DCB00000-570F-4A9B-8D69-199FDBA5723B}".
+func (g GUID) String() string {
+	b := g.bytes()
+	return fmt.Sprintf("{%X-%X-%X-%X-%X}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// IsZero returns true if the GUID is the null GUID (all zeroes).
+func (g GUID) IsZero() bool {
+	return g == GUID{}
+}
+
+// Compare returns -1, 0 or +1 depending on whether g sorts before, equal to
+// or after other. The order is that of the GUIDs' string representations.
+func (g GUID) Compare(other GUID) int {
+	a, b := g.bytes(), other.bytes()
+	return bytes.Compare(a[:], b[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (g GUID) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (g *GUID) UnmarshalText(text []byte) error {
+	guid, err := ParseGUID(string(text))
+	if err != nil {
+		return err
+	}
+	*g = guid
+	return nil
+}
+
+// GUIDFromOLE returns the GUID for an ole.GUID. A nil ole.GUID
+// results in the null GUID.
+func GUIDFromOLE(guid *ole.GUID) GUID {
+	if guid == nil {
+		return GUID{}
+	}
+	return GUID{Data1: guid.Data1, Data2: guid.Data2, Data3: guid.Data3, Data4: guid.Data4}
+}
+
+// OLE returns the GUID as an ole.GUID.
+func (g GUID) OLE() *ole.GUID {
+	return &ole.GUID{Data1: g.Data1, Data2: g.Data2, Data3: g.Data3, Data4: g.Data4}
+}
@@ -0,0 +1,158 @@
+package wnlm
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-ole/go-ole"
+)
+
+func TestParseGUID(t *testing.T) {
+	want := GUID{Data1: 0xDCB00000, Data2: 0x570F, Data3: 0x4A9B, Data4: [8]byte{0x8D, 0x69, 0x19, 0x9F, 0xDB, 0xA5, 0x72, 0x3B}}
+	for _, s := range []string{
+		"{DCB00000-570F-4A9B-8D69-199FDBA5723B}",
+		"DCB00000-570F-4A9B-8D69-199FDBA5723B",
+		"{dcb00000-570f-4a9b-8d69-199fdba5723b}",
+		"dcb00000-570F-4a9b-8D69-199fdba5723B",
+	} {
+		got, err := ParseGUID(s)
+		if err != nil {
+			t.Errorf("ParseGUID(%q) failed: %v", s, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseGUID(%q) = %+v, want %+v", s, got, want)
+		}
+	}
+}
+
+func TestParseGUIDInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"{}",
+		"DCB00000-570F-4A9B-8D69-199FDBA5723",
+		"{DCB00000-570F-4A9B-8D69-199FDBA5723B",
+		"(DCB00000-570F-4A9B-8D69-199FDBA5723B)",
+		"DCB00000_570F-4A9B-8D69-199FDBA5723B",
+		"DCB0000-0570F-4A9B-8D69-199FDBA5723B",
+		"XCB00000-570F-4A9B-8D69-199FDBA5723B",
+		"{DCB00000-570F-4A9B-8D69-199FDBA5723B}x",
+	} {
+		if guid, err := ParseGUID(s); err == nil {
+			t.Errorf("ParseGUID(%q) = %s, want error", s, guid)
+		}
+	}
+}
+
+func TestGUIDString(t *testing.T) {
+	for _, s := range []string{
+		"{00000000-0000-0000-0000-000000000000}",
+		"{DCB00000-570F-4A9B-8D69-199FDBA5723B}",
+		"{FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF}",
+		"{0000000A-000B-000C-0D0E-0F1011121314}",
+	} {
+		guid := MustParseGUID(s)
+		if got := guid.String(); got != s {
+			t.Errorf("MustParseGUID(%q).String() = %q", s, got)
+		}
+		if got := MustParseGUID(strings.ToLower(s)).String(); got != s {
+			t.Errorf("MustParseGUID(%q).String() = %q, want %q", strings.ToLower(s), got, s)
+		}
+	}
+}
+
+func TestMustParseGUIDPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustParseGUID did not panic on an invalid GUID")
+		}
+	}()
+	MustParseGUID("not a GUID")
+}
+
+func TestGUIDIsZero(t *testing.T) {
+	if !(GUID{}).IsZero() {
+		t.Errorf("GUID{}.IsZero() = false")
+	}
+	if MustParseGUID("{00000000-0000-0000-0000-000000000001}").IsZero() {
+		t.Errorf("non-null GUID IsZero() = true")
+	}
+}
+
+func TestGUIDCompare(t *testing.T) {
+	// the order of the GUIDs' strings, which differs from that of their fields' values
+	// (Data1 compares before Data4), and from their little endian memory layout.
+	sorted := []string{
+		"{00000000-0000-0000-0000-000000000000}",
+		"{00000000-0000-0000-0000-000000000001}",
+		"{00000000-0000-0001-0000-000000000000}",
+		"{00000001-0000-0000-0000-000000000000}",
+		"{000000FF-0000-0000-0000-000000000000}",
+		"{01000000-0000-0000-0000-000000000000}",
+		"{FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF}",
+	}
+	guids := []GUID{}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		guids = append(guids, MustParseGUID(sorted[i]))
+	}
+	slices.SortFunc(guids, GUID.Compare)
+	for i, guid := range guids {
+		if guid.String() != sorted[i] {
+			t.Errorf("sorted[%d] = %s, want %s", i, guid, sorted[i])
+		}
+	}
+	if c := guids[1].Compare(guids[1]); c != 0 {
+		t.Errorf("Compare to itself = %d, want 0", c)
+	}
+}
+
+func TestGUIDJSON(t *testing.T) {
+	guid := MustParseGUID("{DCB00000-570F-4A9B-8D69-199FDBA5723B}")
+	value := struct {
+		ID   GUID         `json:"id"`
+		ByID map[GUID]int `json:"byId"`
+	}{ID: guid, ByID: map[GUID]int{guid: 1}}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	want := `{"id":"{DCB00000-570F-4A9B-8D69-199FDBA5723B}","byId":{"{DCB00000-570F-4A9B-8D69-199FDBA5723B}":1}}`
+	if string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+
+	var decoded struct {
+		ID   GUID         `json:"id"`
+		ByID map[GUID]int `json:"byId"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if decoded.ID != guid || decoded.ByID[guid] != 1 {
+		t.Errorf("json.Unmarshal = %+v, want %+v", decoded, value)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"nope"}`), &decoded); err == nil {
+		t.Errorf("json.Unmarshal of an invalid GUID succeeded")
+	}
+}
+
+func TestGUIDOLE(t *testing.T) {
+	s := "{DCB00000-570F-4A9B-8D69-199FDBA5723B}"
+	oleGUID := ole.NewGUID(s)
+	if oleGUID == nil {
+		t.Fatalf("ole.NewGUID(%q) failed", s)
+	}
+	guid := GUIDFromOLE(oleGUID)
+	if guid.String() != s {
+		t.Errorf("GUIDFromOLE = %s, want %s", guid, s)
+	}
+	if !ole.IsEqualGUID(guid.OLE(), oleGUID) {
+		t.Errorf("OLE() = %+v, want %+v", guid.OLE(), oleGUID)
+	}
+	if !GUIDFromOLE(nil).IsZero() {
+		t.Errorf("GUIDFromOLE(nil) is not the null GUID")
+	}
+}
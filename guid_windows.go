@@ -0,0 +1,15 @@
+//go:build windows
+
+package wnlm
+
+import "golang.org/x/sys/windows"
+
+// GUIDFromWindows returns the GUID for a windows.GUID.
+func GUIDFromWindows(guid windows.GUID) GUID {
+	return GUID{Data1: guid.Data1, Data2: guid.Data2, Data3: guid.Data3, Data4: guid.Data4}
+}
+
+// Windows returns the GUID as a windows.GUID.
+func (g GUID) Windows() windows.GUID {
+	return windows.GUID{Data1: g.Data1, Data2: g.Data2, Data3: g.Data3, Data4: g.Data4}
+}
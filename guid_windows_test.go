@@ -0,0 +1,24 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"testing"
+
+	"golang.org/x/sys/windows"
+)
+
+func TestGUIDWindows(t *testing.T) {
+	s := "{DCB00000-570F-4A9B-8D69-199FDBA5723B}"
+	windowsGUID, err := windows.GUIDFromString(s)
+	if err != nil {
+		t.Fatalf("windows.GUIDFromString(%q) failed: %v", s, err)
+	}
+	guid := GUIDFromWindows(windowsGUID)
+	if guid.String() != s {
+		t.Errorf("GUIDFromWindows = %s, want %s", guid, s)
+	}
+	if guid.Windows() != windowsGUID {
+		t.Errorf("Windows() = %v, want %v", guid.Windows(), windowsGUID)
+	}
+}
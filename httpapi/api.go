@@ -0,0 +1,123 @@
+// Package httpapi serves the state of the networks known to an INetworkListManager
+// as a JSON API over HTTP, for inspecting (and managing) them remotely.
+//
+// The API has the following endpoints:
+//
+//	GET /networks               all networks, as []wnlm.NetworkInfo
+//	GET /networks/{guid}        a network, as a wnlm.NetworkInfo
+//	PUT /networks/{guid}        set the properties of a network in a NetworkUpdate, returning the updated wnlm.NetworkInfo
+//	GET /connections            all network connections, as []wnlm.ConnectionInfo
+//	GET /connections/{guid}     a network connection, as a wnlm.ConnectionInfo
+//	GET /connectivity           the overall Connectivity
+//
+// Networks are found through their network connections, so only networks with an active
+// connection are served and can be updated: disconnected and remembered networks are
+// answered with 404 Not Found.
+//
+// Failed requests are answered with an Error. An EventStream of changes to networks and
+// network connections can be served alongside the API, e.g. at GET /events.
+//
+// The API has no authentication of its own. Serving PUT requests lets anyone who can
+// reach the API change networks, e.g. make a public network private, which loosens the
+// firewall profile applied to it. Only serve them behind something which authenticates
+// requests, and which checks their Host header so that web pages cannot reach the API
+// through DNS rebinding.
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// Connectivity is the overall connectivity of a machine, across its networks and network connections.
+type Connectivity struct {
+	Connectivity        wnlm.NLMConnectivity   `json:"connectivity"`
+	IPv4                wnlm.ConnectivityLevel `json:"ipv4"`
+	IPv6                wnlm.ConnectivityLevel `json:"ipv6"`
+	Connected           bool                   `json:"connected"`
+	ConnectedToInternet bool                   `json:"connectedToInternet"`
+}
+
+// ConnectivityOf returns the overall connectivity of the networks and network connections in a Snapshot.
+func ConnectivityOf(snapshot *wnlm.Snapshot) Connectivity {
+	var connectivity wnlm.NLMConnectivity
+	for _, network := range snapshot.Networks {
+		connectivity |= network.Connectivity
+	}
+	for _, conn := range snapshot.Connections {
+		connectivity |= conn.Connectivity
+	}
+	return Connectivity{
+		Connectivity:        connectivity,
+		IPv4:                connectivity.IPv4Level(),
+		IPv6:                connectivity.IPv6Level(),
+		Connected:           !connectivity.IsDisconnected(),
+		ConnectedToInternet: connectivity.IsIPv4Internet() || connectivity.IsIPv6Internet(),
+	}
+}
+
+// NetworkUpdate is the body of PUT /networks/{guid}: the properties of a network
+// to set. Properties which are not set are left unchanged. The category of a network
+// can only be set to public or private, and only networks with an active connection
+// can be updated.
+type NetworkUpdate struct {
+	Name        *string                  `json:"name,omitempty"`
+	Description *string                  `json:"description,omitempty"`
+	Category    *wnlm.NLMNetworkCategory `json:"category,omitempty"`
+}
+
+// Error is the body of the response to a failed request.
+type Error struct {
+	// Message describes the error.
+	Message string `json:"error"`
+	// HRESULT is the HRESULT the request failed with, if any, in hexadecimal e.g. "0x80070005".
+	HRESULT string `json:"hresult,omitempty"`
+	// HRESULTName is the symbolic name of HRESULT, if it is well-known e.g. "E_ACCESSDENIED".
+	HRESULTName string `json:"hresultName,omitempty"`
+}
+
+// errorOf returns the Error describing err.
+func errorOf(err error) *Error {
+	e := &Error{Message: err.Error()}
+	if hr, ok := hresult.FromError(err); ok {
+		e.HRESULT = fmt.Sprintf("0x%08X", uint32(hr))
+		e.HRESULTName = hr.Name()
+	}
+	return e
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the HRESULT of the Error, if any, such that it can be matched with errors.Is.
+func (e *Error) Unwrap() error {
+	hr, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(e.HRESULT), "0x"), 16, 32)
+	if e.HRESULT == "" || err != nil {
+		return nil
+	}
+	return hresult.HRESULT(int32(uint32(hr)))
+}
+
+// statusOf returns the HTTP status code of the response to a request which failed with err.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, hresult.E_NOT_FOUND):
+		return http.StatusNotFound
+	case errors.Is(err, wnlm.ErrReadOnly), errors.Is(err, hresult.E_ACCESSDENIED), errors.Is(err, hresult.E_ELEVATION_REQUIRED):
+		return http.StatusForbidden
+	case errors.Is(err, hresult.E_INVALIDARG):
+		return http.StatusBadRequest
+	case wnlm.IsServerGone(err):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
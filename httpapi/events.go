@@ -0,0 +1,231 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+)
+
+// ResetEvent is the type of the Server-Sent Event sent by an EventStream to a client
+// which missed events, e.g. because it resumed after they were dropped from its buffer.
+// Such clients should fetch the current state again.
+const ResetEvent = "Reset"
+
+// EventStreamOption represents a configuration option for an EventStream.
+type EventStreamOption func(*eventStreamOptions)
+
+type eventStreamOptions struct {
+	bufferSize int
+	heartbeat  time.Duration
+}
+
+// WithBufferSize sets the number of most recent events an EventStream keeps for clients
+// resuming their stream. The default is 1024.
+func WithBufferSize(size int) EventStreamOption {
+	return func(o *eventStreamOptions) { o.bufferSize = max(1, size) }
+}
+
+// WithHeartbeat sets the interval at which an EventStream sends a comment to clients
+// when there are no events, to keep connections (and proxies) from timing out. The
+// default is 15 seconds, and an interval of zero or less disables heartbeats.
+func WithHeartbeat(interval time.Duration) EventStreamOption {
+	return func(o *eventStreamOptions) { o.heartbeat = interval }
+}
+
+// streamEvent is an event buffered by an EventStream, with its ID.
+type streamEvent struct {
+	id    uint64
+	event wnlm.Event
+}
+
+// EventStream is an http.Handler serving the Events of an EventSource as a stream of
+// Server-Sent Events, each with the EventKind as its type (e.g. "NetworkAdded"), an
+// increasing ID, and the Event as JSON data:
+//
+//	id: 42
+//	event: NetworkConnectivityChanged
+//	data: {"kind":"NetworkConnectivityChanged","id":"{...}",...}
+//
+// Clients get the events which happen once they are connected, and may resume their
+// stream with the Last-Event-ID header (or lastEventId query parameter) from the most
+// recent events the EventStream keeps. Clients which missed events get a ResetEvent.
+type EventStream struct {
+	opts        eventStreamOptions
+	unsubscribe func()
+
+	mu sync.Mutex
+	// buffer is a ring of the most recent events, the one with ID id being at
+	// buffer[id%len(buffer)], which holds those after lastID-len(buffer).
+	buffer []streamEvent
+	lastID uint64
+	// changed is closed (and replaced) whenever an event is added.
+	changed chan struct{}
+	closed  chan struct{}
+}
+
+var _ http.Handler = (*EventStream)(nil)
+
+// NewEventStream returns an EventStream of the Events delivered by source, which
+// must be closed to unsubscribe from it.
+func NewEventStream(source wnlm.EventSource, opts ...EventStreamOption) *EventStream {
+	s := &EventStream{
+		opts:    eventStreamOptions{bufferSize: 1024, heartbeat: 15 * time.Second},
+		changed: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	s.buffer = make([]streamEvent, s.opts.bufferSize)
+	s.unsubscribe = source.Subscribe(s.add)
+	return s
+}
+
+// Close unsubscribes from the EventSource and ends every stream being served.
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		return
+	default:
+	}
+	s.unsubscribe()
+	close(s.closed)
+}
+
+// add buffers an event and wakes up the streams being served.
+func (s *EventStream) add(event wnlm.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	s.buffer[s.lastID%uint64(len(s.buffer))] = streamEvent{id: s.lastID, event: event}
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// since returns the buffered events after the one with the given ID, whether some were
+// missed (no longer buffered, or the ID is unknown), and a channel closed on the next event.
+func (s *EventStream) since(id uint64) (events []streamEvent, missed bool, changed <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size := uint64(len(s.buffer))
+	first := s.lastID - min(s.lastID, size) + 1 // the ID of the oldest buffered event
+	from := max(id+1, first)
+	if id > s.lastID {
+		from, missed = first, true
+	} else {
+		missed = from > id+1
+	}
+	for next := from; next <= s.lastID; next++ {
+		events = append(events, s.buffer[next%size])
+	}
+	return events, missed, s.changed
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *EventStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-s.closed:
+		writeError(w, http.StatusServiceUnavailable, errors.New("event stream closed"))
+		return
+	default:
+	}
+
+	s.mu.Lock()
+	last := s.lastID
+	s.mu.Unlock()
+	if resume := r.Header.Get("Last-Event-ID"); resume != "" || r.URL.Query().Has("lastEventId") {
+		if resume == "" {
+			resume = r.URL.Query().Get("lastEventId")
+		}
+		id, err := strconv.ParseUint(strings.TrimSpace(resume), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid last event ID %q", resume))
+			return
+		}
+		last = id
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(w, ": stream of network changes\n\n"); err != nil {
+		return
+	}
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	// heartbeat stays nil (never ready) if heartbeats are disabled.
+	var heartbeat <-chan time.Time
+	var ticker *time.Ticker
+	if s.opts.heartbeat > 0 {
+		ticker = time.NewTicker(s.opts.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	for {
+		events, missed, changed := s.since(last)
+		if missed {
+			// the reset has the ID of the event before those sent next (0 if none were
+			// ever buffered), such that clients resuming after it get them.
+			var reset uint64
+			if len(events) > 0 {
+				reset = events[0].id - 1
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: {}\n\n", reset, ResetEvent); err != nil {
+				return
+			}
+			last = reset
+		}
+		for _, event := range events {
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			last = event.id
+		}
+		if len(events) > 0 || missed {
+			if err := controller.Flush(); err != nil {
+				return
+			}
+			if ticker != nil {
+				ticker.Reset(s.opts.heartbeat)
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-heartbeat:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// writeEvent writes an event as a Server-Sent Event.
+func writeEvent(w io.Writer, event streamEvent) error {
+	data, err := json.Marshal(event.event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.id, event.event.Kind, data)
+	return err
+}
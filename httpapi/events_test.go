@@ -0,0 +1,281 @@
+package httpapi_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/httpapi"
+)
+
+// testSource is an EventSource delivering the events it is given.
+type testSource struct {
+	mu     sync.Mutex
+	handle func(wnlm.Event)
+}
+
+func (s *testSource) Subscribe(handle func(wnlm.Event)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handle = handle
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.handle = nil
+	}
+}
+
+// publish delivers an event of the given kind about each of the given networks.
+func (s *testSource) publish(kind wnlm.EventKind, ids ...wnlm.GUID) {
+	s.mu.Lock()
+	handle := s.handle
+	s.mu.Unlock()
+	for _, id := range ids {
+		if handle != nil {
+			handle(wnlm.Event{Kind: kind, ID: id, NetworkID: id})
+		}
+	}
+}
+
+// subscribed returns true if the source has a subscriber.
+func (s *testSource) subscribed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handle != nil
+}
+
+// sseMessage is a Server-Sent Event, or a comment.
+type sseMessage struct {
+	id, event, data, comment string
+}
+
+// sseStream reads the messages of a stream of Server-Sent Events.
+type sseStream struct {
+	resp   *http.Response
+	reader *bufio.Reader
+}
+
+// openStream requests a stream from server with the given Last-Event-ID header (if
+// any), and reads its opening comment. The stream is closed at the end of the test.
+func openStream(t *testing.T, server *httptest.Server, path, lastEventID string) *sseStream {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("GET %s = %s (%s)", path, resp.Status, resp.Header.Get("Content-Type"))
+	}
+	stream := &sseStream{resp: resp, reader: bufio.NewReader(resp.Body)}
+	if msg := stream.next(t); msg.comment != "stream of network changes" {
+		t.Fatalf("first message = %+v, want the opening comment", msg)
+	}
+	return stream
+}
+
+// next reads the next message of the stream.
+func (s *sseStream) next(t *testing.T) sseMessage {
+	t.Helper()
+	msg, err := s.read()
+	if err != nil {
+		t.Fatalf("failed to read event: %v", err)
+	}
+	return msg
+}
+
+// read reads the next message of the stream.
+func (s *sseStream) read() (sseMessage, error) {
+	var msg sseMessage
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return msg, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return msg, nil
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "":
+			msg.comment = value
+		case "id":
+			msg.id = value
+		case "event":
+			msg.event = value
+		case "data":
+			msg.data = value
+		}
+	}
+}
+
+// expectEvent reads the next message of the stream, failing the test unless it is an event with the given ID and kind.
+func (s *sseStream) expectEvent(t *testing.T, id string, kind string) sseMessage {
+	t.Helper()
+	msg := s.next(t)
+	if msg.id != id || msg.event != kind {
+		t.Fatalf("event = %+v, want event %s with ID %s", msg, kind, id)
+	}
+	return msg
+}
+
+// newEventServer returns a server of an EventStream of a testSource, closed at the end of the test.
+func newEventServer(t *testing.T, opts ...httpapi.EventStreamOption) (*httptest.Server, *httpapi.EventStream, *testSource) {
+	source := &testSource{}
+	events := httpapi.NewEventStream(source, opts...)
+	server := httptest.NewServer(events)
+	t.Cleanup(func() {
+		events.Close()
+		server.Close()
+	})
+	return server, events, source
+}
+
+func TestEventStream(t *testing.T) {
+	server, _, source := newEventServer(t)
+	source.publish(wnlm.EventNetworkAdded, fake.SampleOfficeID) // before connecting, not sent
+	stream := openStream(t, server, "/", "")
+
+	source.publish(wnlm.EventNetworkAdded, fake.SampleHomeID)
+	source.publish(wnlm.EventConnectionConnectivityChanged, fake.SampleWiFiID)
+	msg := stream.expectEvent(t, "2", "NetworkAdded")
+	var event wnlm.Event
+	if err := json.Unmarshal([]byte(msg.data), &event); err != nil || event.Kind != wnlm.EventNetworkAdded || event.ID != fake.SampleHomeID {
+		t.Errorf("event data = %s (%v), want the home network added", msg.data, err)
+	}
+	stream.expectEvent(t, "3", "ConnectionConnectivityChanged")
+}
+
+func TestEventStreamResume(t *testing.T) {
+	server, _, source := newEventServer(t)
+	source.publish(wnlm.EventNetworkAdded, fake.SampleHomeID, fake.SampleOfficeID, fake.SampleWiFiID)
+
+	stream := openStream(t, server, "/", "1")
+	stream.expectEvent(t, "2", "NetworkAdded")
+	stream.expectEvent(t, "3", "NetworkAdded")
+
+	stream = openStream(t, server, "/?lastEventId=2", "")
+	stream.expectEvent(t, "3", "NetworkAdded")
+	source.publish(wnlm.EventNetworkDeleted, fake.SampleHomeID)
+	stream.expectEvent(t, "4", "NetworkDeleted")
+
+	resp, err := server.Client().Get(server.URL + "/?lastEventId=latest")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET with an invalid last event ID = %s, want 400", resp.Status)
+	}
+}
+
+func TestEventStreamBufferOverflow(t *testing.T) {
+	server, _, source := newEventServer(t, httpapi.WithBufferSize(2))
+	source.publish(wnlm.EventNetworkAdded, fake.SampleHomeID, fake.SampleOfficeID, fake.SampleWiFiID, fake.SampleEthernetID, fake.SampleAdapterID)
+
+	// events 2 and 3 were dropped from the buffer
+	stream := openStream(t, server, "/", "1")
+	if msg := stream.expectEvent(t, "3", httpapi.ResetEvent); msg.data != "{}" {
+		t.Errorf("reset data = %q, want {}", msg.data)
+	}
+	stream.expectEvent(t, "4", "NetworkAdded")
+	stream.expectEvent(t, "5", "NetworkAdded")
+
+	// an ID from before a restart of the server is unknown
+	stream = openStream(t, server, "/", "42")
+	stream.expectEvent(t, "3", httpapi.ResetEvent)
+	stream.expectEvent(t, "4", "NetworkAdded")
+
+	// nothing was missed by resuming from the last event
+	stream = openStream(t, server, "/", "5")
+	source.publish(wnlm.EventNetworkDeleted, fake.SampleHomeID)
+	stream.expectEvent(t, "6", "NetworkDeleted")
+
+	// the buffer keeps the most recent events as it wraps around
+	source.publish(wnlm.EventNetworkDeleted, fake.SampleOfficeID, fake.SampleWiFiID, fake.SampleEthernetID)
+	stream = openStream(t, server, "/", "6")
+	stream.expectEvent(t, "7", httpapi.ResetEvent)
+	stream.expectEvent(t, "8", "NetworkDeleted")
+	stream.expectEvent(t, "9", "NetworkDeleted")
+	stream = openStream(t, server, "/", "8")
+	stream.expectEvent(t, "9", "NetworkDeleted")
+}
+
+func TestEventStreamResetWithoutEvents(t *testing.T) {
+	server, _, source := newEventServer(t)
+	stream := openStream(t, server, "/", "7")
+	stream.expectEvent(t, "0", httpapi.ResetEvent)
+	source.publish(wnlm.EventNetworkAdded, fake.SampleHomeID)
+	stream.expectEvent(t, "1", "NetworkAdded")
+}
+
+func TestEventStreamHeartbeat(t *testing.T) {
+	server, _, source := newEventServer(t, httpapi.WithHeartbeat(10*time.Millisecond))
+	stream := openStream(t, server, "/", "")
+	if msg := stream.next(t); msg.comment != "heartbeat" {
+		t.Errorf("message = %+v, want a heartbeat", msg)
+	}
+	source.publish(wnlm.EventNetworkAdded, fake.SampleHomeID)
+	for {
+		msg := stream.next(t)
+		if msg.comment == "heartbeat" {
+			continue
+		}
+		if msg.id != "1" {
+			t.Errorf("message = %+v, want the event", msg)
+		}
+		break
+	}
+}
+
+func TestEventStreamNoHeartbeat(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		server, _, source := newEventServer(t, httpapi.WithHeartbeat(interval))
+		stream := openStream(t, server, "/", "")
+		source.publish(wnlm.EventNetworkAdded, fake.SampleHomeID)
+		stream.expectEvent(t, "1", "NetworkAdded")
+	}
+}
+
+func TestEventStreamClose(t *testing.T) {
+	server, events, source := newEventServer(t)
+	stream := openStream(t, server, "/", "")
+
+	events.Close()
+	events.Close()
+	if source.subscribed() {
+		t.Error("EventStream still subscribed once closed")
+	}
+	if _, err := stream.read(); !errors.Is(err, io.EOF) {
+		t.Errorf("reading a closed stream = %v, want EOF", err)
+	}
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("GET once closed = %s, want 503", resp.Status)
+	}
+}
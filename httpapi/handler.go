@@ -0,0 +1,196 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// maxBodySize is the maximum size of the body of a request.
+const maxBodySize = 1 << 20
+
+// handler serves the API for an INetworkListManager.
+type handler struct {
+	nlm wnlm.INetworkListManager
+}
+
+// NewHandler returns an http.Handler serving the API (see the package documentation)
+// for nlm, which must be safe for concurrent use (e.g. from a Session). Pass a manager
+// from wnlm.NewReadOnlyNetworkListManager to serve the API without PUT requests.
+func NewHandler(nlm wnlm.INetworkListManager) http.Handler {
+	h := &handler{nlm: nlm}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /networks", h.getNetworks)
+	mux.HandleFunc("GET /networks/{guid}", h.getNetwork)
+	mux.HandleFunc("PUT /networks/{guid}", h.putNetwork)
+	mux.HandleFunc("GET /connections", h.getConnections)
+	mux.HandleFunc("GET /connections/{guid}", h.getConnection)
+	mux.HandleFunc("GET /connectivity", h.getConnectivity)
+	return mux
+}
+
+// getNetworks serves GET /networks.
+func (h *handler) getNetworks(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := wnlm.TakeSnapshot(h.nlm)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot.Networks)
+}
+
+// getNetwork serves GET /networks/{guid}.
+func (h *handler) getNetwork(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathGUID(w, r)
+	if !ok {
+		return
+	}
+	network, err := h.network(id)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, network)
+}
+
+// putNetwork serves PUT /networks/{guid}.
+func (h *handler) putNetwork(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathGUID(w, r)
+	if !ok {
+		return
+	}
+	var update NetworkUpdate
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid network update: %w", err))
+		return
+	}
+	if update.Name == nil && update.Description == nil && update.Category == nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid network update: no properties to set"))
+		return
+	}
+	if update.Category != nil && *update.Category != wnlm.NLMNetworkCategoryPublic && *update.Category != wnlm.NLMNetworkCategoryPrivate {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid network update: category must be Public or Private, not %s", *update.Category))
+		return
+	}
+
+	if err := h.updateNetwork(id, update); err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	network, err := h.network(id)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, network)
+}
+
+// getConnections serves GET /connections.
+func (h *handler) getConnections(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := wnlm.TakeSnapshot(h.nlm)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot.Connections)
+}
+
+// getConnection serves GET /connections/{guid}.
+func (h *handler) getConnection(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathGUID(w, r)
+	if !ok {
+		return
+	}
+	snapshot, err := wnlm.TakeSnapshot(h.nlm)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	conn, ok := snapshot.Connection(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("network connection %s not found: %w", id, hresult.E_NOT_FOUND))
+		return
+	}
+	writeJSON(w, http.StatusOK, conn)
+}
+
+// getConnectivity serves GET /connectivity.
+func (h *handler) getConnectivity(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := wnlm.TakeSnapshot(h.nlm)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ConnectivityOf(snapshot))
+}
+
+// network returns the network with the given GUID, failing with hresult.E_NOT_FOUND if there is none.
+func (h *handler) network(id wnlm.GUID) (wnlm.NetworkInfo, error) {
+	snapshot, err := wnlm.TakeSnapshot(h.nlm)
+	if err != nil {
+		return wnlm.NetworkInfo{}, err
+	}
+	network, ok := snapshot.Network(id)
+	if !ok {
+		return wnlm.NetworkInfo{}, fmt.Errorf("network %s not found: %w", id, hresult.E_NOT_FOUND)
+	}
+	return network, nil
+}
+
+// updateNetwork sets the properties of the network with the given GUID, which is only found
+// if it has an active connection.
+func (h *handler) updateNetwork(id wnlm.GUID, update NetworkUpdate) error {
+	network, err := wnlm.FindNetwork(h.nlm, id)
+	if errors.Is(err, hresult.E_NOT_FOUND) {
+		return fmt.Errorf("network %s has no active connection (disconnected and remembered networks cannot be updated): %w", id, hresult.E_NOT_FOUND)
+	}
+	if err != nil {
+		return err
+	}
+	defer network.Release()
+
+	if update.Name != nil {
+		if err := network.SetName(*update.Name); err != nil {
+			return fmt.Errorf("failed to set name of network %s: %w", id, err)
+		}
+	}
+	if update.Description != nil {
+		if err := network.SetDescription(*update.Description); err != nil {
+			return fmt.Errorf("failed to set description of network %s: %w", id, err)
+		}
+	}
+	if update.Category != nil {
+		if err := network.SetCategory(*update.Category); err != nil {
+			return fmt.Errorf("failed to set category of network %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// pathGUID returns the GUID in the path of a request, answering it with an error if it is invalid.
+func pathGUID(w http.ResponseWriter, r *http.Request) (wnlm.GUID, bool) {
+	id, err := wnlm.ParseGUID(r.PathValue("guid"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return wnlm.GUID{}, false
+	}
+	return id, true
+}
+
+// writeJSON writes a response with the given status code and value as its JSON body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a response with the given status code and the Error describing err as its body.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorOf(err))
+}
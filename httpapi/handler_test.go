@@ -0,0 +1,209 @@
+package httpapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/httpapi"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// do serves a request with h, and returns the response with its body decoded into v, if not nil.
+func do(t *testing.T, h http.Handler, method, path, body string, v any) *http.Response {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	resp := rec.Result()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: failed to decode response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+func TestHandlerGet(t *testing.T) {
+	h := httpapi.NewHandler(fake.NewSample())
+
+	var networks []wnlm.NetworkInfo
+	if resp := do(t, h, "GET", "/networks", "", &networks); resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("GET /networks = %s (%s)", resp.Status, resp.Header.Get("Content-Type"))
+	}
+	if len(networks) != 2 || networks[0].ID != fake.SampleHomeID || networks[1].ID != fake.SampleOfficeID {
+		t.Errorf("GET /networks = %+v", networks)
+	}
+
+	var network wnlm.NetworkInfo
+	if resp := do(t, h, "GET", "/networks/"+fake.SampleOfficeID.String(), "", &network); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /networks/{guid} = %s", resp.Status)
+	}
+	if network.Name != "Office" || network.DomainType != wnlm.NLMDomainTypeDomainAuthenticated || network.Connections[0] != fake.SampleEthernetID {
+		t.Errorf("GET /networks/{guid} = %+v", network)
+	}
+
+	var conns []wnlm.ConnectionInfo
+	if resp := do(t, h, "GET", "/connections", "", &conns); resp.StatusCode != http.StatusOK || len(conns) != 2 {
+		t.Errorf("GET /connections = %s, %+v", resp.Status, conns)
+	}
+	var conn wnlm.ConnectionInfo
+	if resp := do(t, h, "GET", "/connections/"+fake.SampleWiFiID.String(), "", &conn); resp.StatusCode != http.StatusOK || conn.NetworkID != fake.SampleHomeID {
+		t.Errorf("GET /connections/{guid} = %s, %+v", resp.Status, conn)
+	}
+
+	var connectivity httpapi.Connectivity
+	if resp := do(t, h, "GET", "/connectivity", "", &connectivity); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /connectivity = %s", resp.Status)
+	}
+	want := httpapi.Connectivity{
+		Connectivity:        wnlm.NLMConnectivityIPv4Internet | wnlm.NLMConnectivityIPv6LocalNetwork,
+		IPv4:                wnlm.ConnectivityLevelInternet,
+		IPv6:                wnlm.ConnectivityLevelLocalNetwork,
+		Connected:           true,
+		ConnectedToInternet: true,
+	}
+	if connectivity != want {
+		t.Errorf("GET /connectivity = %+v, want %+v", connectivity, want)
+	}
+}
+
+func TestHandlerNotFound(t *testing.T) {
+	h := httpapi.NewHandler(fake.NewSample())
+	unknownID := "{12345678-1234-1234-1234-123456789ABC}"
+	for _, path := range []string{"/networks/" + unknownID, "/connections/" + unknownID} {
+		var apiErr httpapi.Error
+		resp := do(t, h, "GET", path, "", &apiErr)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s = %s, want 404", path, resp.Status)
+		}
+		if apiErr.HRESULTName != "E_NOT_FOUND" || !errors.Is(&apiErr, hresult.E_NOT_FOUND) || !strings.Contains(apiErr.Message, unknownID) {
+			t.Errorf("GET %s error = %+v, want E_NOT_FOUND", path, apiErr)
+		}
+	}
+	var apiErr httpapi.Error
+	if resp := do(t, h, "PUT", "/networks/"+unknownID, `{"name":"Cafe"}`, &apiErr); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("PUT /networks/%s = %s, want 404", unknownID, resp.Status)
+	}
+	if resp := do(t, h, "GET", "/networks/home", "", &apiErr); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET /networks/home = %s, want 400", resp.Status)
+	}
+}
+
+func TestHandlerPutNoActiveConnection(t *testing.T) {
+	m := fake.NewSample()
+	m.RemoveConnection(fake.SampleWiFiID)
+	h := httpapi.NewHandler(m)
+
+	var apiErr httpapi.Error
+	if resp := do(t, h, "PUT", "/networks/"+fake.SampleHomeID.String(), `{"name":"House"}`, &apiErr); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("PUT /networks/{guid} = %s, want 404", resp.Status)
+	}
+	if !errors.Is(&apiErr, hresult.E_NOT_FOUND) || !strings.Contains(apiErr.Message, "has no active connection") {
+		t.Errorf("PUT /networks/{guid} error = %+v, want no active connection", apiErr)
+	}
+	if network, _ := m.Network(fake.SampleHomeID); network.Name != "Home" {
+		t.Errorf("network name = %q, want it unchanged", network.Name)
+	}
+}
+
+func TestHandlerPut(t *testing.T) {
+	m := fake.NewSample()
+	h := httpapi.NewHandler(m)
+
+	var network wnlm.NetworkInfo
+	resp := do(t, h, "PUT", "/networks/"+fake.SampleHomeID.String(), `{"name":"House","category":"public"}`, &network)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT = %s", resp.Status)
+	}
+	if network.Name != "House" || network.Category != wnlm.NLMNetworkCategoryPublic || network.Description != "Home network" {
+		t.Errorf("PUT = %+v, want the updated network", network)
+	}
+	if state, _ := m.Network(fake.SampleHomeID); state.Name != "House" || state.Category != wnlm.NLMNetworkCategoryPublic {
+		t.Errorf("network = %+v, want it updated", state)
+	}
+
+	for _, body := range []string{
+		``,
+		`{}`,
+		`{"ssid":"House"}`,
+		`{"category":"DomainAuthenticated"}`,
+		`{"category":2}`,
+		`{"category":7}`,
+		`{"category":"work"}`,
+	} {
+		var apiErr httpapi.Error
+		if resp := do(t, h, "PUT", "/networks/"+fake.SampleOfficeID.String(), body, &apiErr); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("PUT %s = %s, want 400", body, resp.Status)
+		}
+		if !strings.HasPrefix(apiErr.Message, "invalid network update: ") {
+			t.Errorf("PUT %s error = %q", body, apiErr.Message)
+		}
+	}
+	if state, _ := m.Network(fake.SampleOfficeID); state.Category != wnlm.NLMNetworkCategoryPublic {
+		t.Errorf("office category = %s after invalid updates, want it unchanged", state.Category)
+	}
+}
+
+func TestHandlerReadOnly(t *testing.T) {
+	m := fake.NewSample()
+	h := httpapi.NewHandler(wnlm.NewReadOnlyNetworkListManager(m))
+	var apiErr httpapi.Error
+	if resp := do(t, h, "PUT", "/networks/"+fake.SampleHomeID.String(), `{"name":"House"}`, &apiErr); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("PUT = %s, want 403", resp.Status)
+	}
+	if state, _ := m.Network(fake.SampleHomeID); state.Name != "Home" {
+		t.Errorf("network renamed to %q through a read-only handler", state.Name)
+	}
+	if resp := do(t, h, "GET", "/networks/"+fake.SampleHomeID.String(), "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET = %s, want 200", resp.Status)
+	}
+}
+
+func TestHandlerStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		name   string
+	}{
+		{hresult.E_ACCESSDENIED, http.StatusForbidden, "E_ACCESSDENIED"},
+		{hresult.E_ELEVATION_REQUIRED, http.StatusForbidden, "E_ELEVATION_REQUIRED"},
+		{hresult.E_INVALIDARG, http.StatusBadRequest, "E_INVALIDARG"},
+		{hresult.RPC_E_DISCONNECTED, http.StatusServiceUnavailable, "RPC_E_DISCONNECTED"},
+		{hresult.RPC_S_SERVER_UNAVAILABLE, http.StatusServiceUnavailable, "RPC_S_SERVER_UNAVAILABLE"},
+		{hresult.E_FAIL, http.StatusInternalServerError, "E_FAIL"},
+		{errors.New("boom"), http.StatusInternalServerError, ""},
+	}
+	for _, test := range tests {
+		m := fake.NewSample()
+		m.SetFault(func(iface, method string) error {
+			if method == "SetName" {
+				return test.err
+			}
+			return nil
+		})
+		h := httpapi.NewHandler(m)
+		var apiErr httpapi.Error
+		if resp := do(t, h, "PUT", "/networks/"+fake.SampleHomeID.String(), `{"name":"House"}`, &apiErr); resp.StatusCode != test.status {
+			t.Errorf("PUT failing with %v = %s, want %d", test.err, resp.Status, test.status)
+		}
+		if apiErr.HRESULTName != test.name {
+			t.Errorf("PUT failing with %v error = %+v, want %s", test.err, apiErr, test.name)
+		}
+	}
+
+	m := fake.NewSample()
+	m.SetFault(func(iface, method string) error {
+		if method == "GetNetworkConnections" {
+			return hresult.RPC_E_SERVER_DIED
+		}
+		return nil
+	})
+	if resp := do(t, httpapi.NewHandler(m), "GET", "/networks", "", nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("GET with the server gone = %s, want 503", resp.Status)
+	}
+}
@@ -0,0 +1,374 @@
+package wnlm
+
+import "time"
+
+// Call is a single call made to a method of an intercepted object, as seen by an Interceptor.
+type Call struct {
+	// Interface is the name of the interface of the object, e.g. "INetwork".
+	Interface string
+	// Method is the name of the method called, e.g. "GetName".
+	Method string
+	// Target is the (non-intercepted) object the method is called on.
+	Target any
+	// Args holds the arguments of the call, which an Interceptor may replace before
+	// invoking the next Invoker. Methods that take no arguments have no Args.
+	Args []any
+	// Results holds the results of the call other than its error, which an Interceptor
+	// may replace once the next Invoker returns. Results must keep the types returned by
+	// the method (e.g. a string for GetName), any other value is returned as a zero value.
+	Results []any
+	// Err is the error returned by the call.
+	Err error
+	// Duration is the time taken by the (last) invocation of the underlying method.
+	Duration time.Duration
+}
+
+// Invoker performs a Call, setting its Results, Err and Duration.
+type Invoker func(*Call)
+
+// Interceptor intercepts a Call. It can inspect or change the Call before and after
+// invoking next to proceed with it, invoke next more than once (e.g. to retry it),
+// or not invoke it at all, setting the Results or Err of the Call itself.
+type Interceptor func(call *Call, next Invoker)
+
+// ChainInterceptors returns an Interceptor which runs the given interceptors in
+// order, the first being the outermost one.
+func ChainInterceptors(interceptors ...Interceptor) Interceptor {
+	return func(call *Call, next Invoker) {
+		chained := next
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], chained
+			chained = func(call *Call) { interceptor(call, inner) }
+		}
+		chained(call)
+	}
+}
+
+// InterceptNetworkListManager returns an INetworkListManager which runs every call made
+// to nlm, and to every INetwork, INetworkConnection and IEnumNetworkConnections obtained
+// from it, through the given interceptors (see ChainInterceptors). Objects returned by
+// intercepted calls are themselves intercepted.
+func InterceptNetworkListManager(nlm INetworkListManager, interceptors ...Interceptor) INetworkListManager {
+	return &interceptedNetworkListManager{chain: ChainInterceptors(interceptors...), nlm: nlm}
+}
+
+// intercept runs a call to a method of target through chain, with invoke performing the call.
+func intercept(chain Interceptor, iface string, method string, target any, args []any, invoke func(*Call)) *Call {
+	call := &Call{Interface: iface, Method: method, Target: target, Args: args}
+	chain(call, func(call *Call) {
+		start := time.Now()
+		invoke(call)
+		call.Duration = time.Since(start)
+	})
+	return call
+}
+
+// callArg returns the i-th argument of a call, or the zero value if it is missing or of another type.
+func callArg[T any](call *Call, i int) T {
+	var value T
+	if i < len(call.Args) {
+		value, _ = call.Args[i].(T)
+	}
+	return value
+}
+
+// callResult returns the i-th result of a call, or the zero value if it is missing or of another type.
+func callResult[T any](call *Call, i int) T {
+	var value T
+	if i < len(call.Results) {
+		value, _ = call.Results[i].(T)
+	}
+	return value
+}
+
+// interceptedNetworkListManager is an INetworkListManager whose calls run through an Interceptor.
+type interceptedNetworkListManager struct {
+	chain Interceptor
+	nlm   INetworkListManager
+}
+
+// interceptedNetwork is an INetwork whose calls run through an Interceptor.
+type interceptedNetwork struct {
+	chain   Interceptor
+	network INetwork
+}
+
+// interceptedNetworkConnection is an INetworkConnection whose calls run through an Interceptor.
+type interceptedNetworkConnection struct {
+	chain Interceptor
+	conn  INetworkConnection
+}
+
+// interceptedEnumNetworkConnections is an IEnumNetworkConnections whose calls run through an Interceptor.
+type interceptedEnumNetworkConnections struct {
+	chain Interceptor
+	enum  IEnumNetworkConnections
+	conns []INetworkConnection
+}
+
+// interceptedNetworkConnections returns an IEnumNetworkConnections whose calls, and
+// those of each of its INetworkConnection objects, run through chain.
+func interceptedNetworkConnections(chain Interceptor, enum IEnumNetworkConnections) IEnumNetworkConnections {
+	if enum == nil {
+		return nil
+	}
+	conns := make([]INetworkConnection, 0, enum.Size())
+	enum.ForEach(func(_ int, conn INetworkConnection) bool {
+		conns = append(conns, &interceptedNetworkConnection{chain: chain, conn: conn})
+		return true
+	})
+	return &interceptedEnumNetworkConnections{chain: chain, enum: enum, conns: conns}
+}
+
+// interceptedNetworkOf returns an INetwork whose calls run through chain.
+func interceptedNetworkOf(chain Interceptor, network INetwork) INetwork {
+	if network == nil {
+		return nil
+	}
+	return &interceptedNetwork{chain: chain, network: network}
+}
+
+// GetNetworkConnections returns all network connections for the system.
+func (m *interceptedNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	call := intercept(m.chain, "INetworkListManager", "GetNetworkConnections", m.nlm, nil, func(call *Call) {
+		conns, err := m.nlm.GetNetworkConnections()
+		call.Results, call.Err = []any{conns}, err
+	})
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return interceptedNetworkConnections(m.chain, callResult[IEnumNetworkConnections](call, 0)), nil
+}
+
+// Release releases the INetworkListManager object.
+func (m *interceptedNetworkListManager) Release() {
+	intercept(m.chain, "INetworkListManager", "Release", m.nlm, nil, func(*Call) { m.nlm.Release() })
+}
+
+// call runs a call to a method of the network through the Interceptor.
+func (n *interceptedNetwork) call(method string, args []any, invoke func(*Call)) *Call {
+	return intercept(n.chain, "INetwork", method, n.network, args, invoke)
+}
+
+// GetName gets the name of this network.
+func (n *interceptedNetwork) GetName() (string, error) {
+	call := n.call("GetName", nil, func(call *Call) {
+		name, err := n.network.GetName()
+		call.Results, call.Err = []any{name}, err
+	})
+	return callResult[string](call, 0), call.Err
+}
+
+// SetName sets the name of this network.
+func (n *interceptedNetwork) SetName(name string) error {
+	return n.call("SetName", []any{name}, func(call *Call) {
+		call.Err = n.network.SetName(callArg[string](call, 0))
+	}).Err
+}
+
+// GetDescription gets the description of this network.
+func (n *interceptedNetwork) GetDescription() (string, error) {
+	call := n.call("GetDescription", nil, func(call *Call) {
+		description, err := n.network.GetDescription()
+		call.Results, call.Err = []any{description}, err
+	})
+	return callResult[string](call, 0), call.Err
+}
+
+// SetDescription sets the description of this network.
+func (n *interceptedNetwork) SetDescription(description string) error {
+	return n.call("SetDescription", []any{description}, func(call *Call) {
+		call.Err = n.network.SetDescription(callArg[string](call, 0))
+	}).Err
+}
+
+// GetNetworkId gets the unique identifier of this network.
+func (n *interceptedNetwork) GetNetworkId() (GUID, error) {
+	call := n.call("GetNetworkId", nil, func(call *Call) {
+		id, err := n.network.GetNetworkId()
+		call.Results, call.Err = []any{id}, err
+	})
+	return callResult[GUID](call, 0), call.Err
+}
+
+// GetDomainType gets the domain type of this network.
+func (n *interceptedNetwork) GetDomainType() (NLMDomainType, error) {
+	call := n.call("GetDomainType", nil, func(call *Call) {
+		domainType, err := n.network.GetDomainType()
+		call.Results, call.Err = []any{domainType}, err
+	})
+	return callResult[NLMDomainType](call, 0), call.Err
+}
+
+// GetNetworkConnections gets the network connections of this network.
+func (n *interceptedNetwork) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	call := n.call("GetNetworkConnections", nil, func(call *Call) {
+		conns, err := n.network.GetNetworkConnections()
+		call.Results, call.Err = []any{conns}, err
+	})
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return interceptedNetworkConnections(n.chain, callResult[IEnumNetworkConnections](call, 0)), nil
+}
+
+// GetTimeCreatedAndConnected gets the times at which this network was created and last connected to.
+func (n *interceptedNetwork) GetTimeCreatedAndConnected() (time.Time, time.Time, error) {
+	call := n.call("GetTimeCreatedAndConnected", nil, func(call *Call) {
+		created, connected, err := n.network.GetTimeCreatedAndConnected()
+		call.Results, call.Err = []any{created, connected}, err
+	})
+	return callResult[time.Time](call, 0), callResult[time.Time](call, 1), call.Err
+}
+
+// IsConnectedToInternet returns true if this network is connected to the internet.
+func (n *interceptedNetwork) IsConnectedToInternet() (bool, error) {
+	call := n.call("IsConnectedToInternet", nil, func(call *Call) {
+		connected, err := n.network.IsConnectedToInternet()
+		call.Results, call.Err = []any{connected}, err
+	})
+	return callResult[bool](call, 0), call.Err
+}
+
+// IsConnected returns true if this network is connected.
+func (n *interceptedNetwork) IsConnected() (bool, error) {
+	call := n.call("IsConnected", nil, func(call *Call) {
+		connected, err := n.network.IsConnected()
+		call.Results, call.Err = []any{connected}, err
+	})
+	return callResult[bool](call, 0), call.Err
+}
+
+// GetConnectivity gets the connectivity of this network.
+func (n *interceptedNetwork) GetConnectivity() (NLMConnectivity, error) {
+	call := n.call("GetConnectivity", nil, func(call *Call) {
+		connectivity, err := n.network.GetConnectivity()
+		call.Results, call.Err = []any{connectivity}, err
+	})
+	return callResult[NLMConnectivity](call, 0), call.Err
+}
+
+// GetCategory gets the category of this network.
+func (n *interceptedNetwork) GetCategory() (NLMNetworkCategory, error) {
+	call := n.call("GetCategory", nil, func(call *Call) {
+		category, err := n.network.GetCategory()
+		call.Results, call.Err = []any{category}, err
+	})
+	return callResult[NLMNetworkCategory](call, 0), call.Err
+}
+
+// SetCategory sets the category of this network.
+func (n *interceptedNetwork) SetCategory(category NLMNetworkCategory) error {
+	return n.call("SetCategory", []any{category}, func(call *Call) {
+		call.Err = n.network.SetCategory(callArg[NLMNetworkCategory](call, 0))
+	}).Err
+}
+
+// Release releases the INetwork object.
+func (n *interceptedNetwork) Release() {
+	n.call("Release", nil, func(*Call) { n.network.Release() })
+}
+
+// call runs a call to a method of the network connection through the Interceptor.
+func (nc *interceptedNetworkConnection) call(method string, invoke func(*Call)) *Call {
+	return intercept(nc.chain, "INetworkConnection", method, nc.conn, nil, invoke)
+}
+
+// GetNetwork gets the network of this network connection.
+func (nc *interceptedNetworkConnection) GetNetwork() (INetwork, error) {
+	call := nc.call("GetNetwork", func(call *Call) {
+		network, err := nc.conn.GetNetwork()
+		call.Results, call.Err = []any{network}, err
+	})
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return interceptedNetworkOf(nc.chain, callResult[INetwork](call, 0)), nil
+}
+
+// IsConnectedToInternet returns true if this network connection is connected to the internet.
+func (nc *interceptedNetworkConnection) IsConnectedToInternet() (bool, error) {
+	call := nc.call("IsConnectedToInternet", func(call *Call) {
+		connected, err := nc.conn.IsConnectedToInternet()
+		call.Results, call.Err = []any{connected}, err
+	})
+	return callResult[bool](call, 0), call.Err
+}
+
+// IsConnected returns true if this network connection is connected.
+func (nc *interceptedNetworkConnection) IsConnected() (bool, error) {
+	call := nc.call("IsConnected", func(call *Call) {
+		connected, err := nc.conn.IsConnected()
+		call.Results, call.Err = []any{connected}, err
+	})
+	return callResult[bool](call, 0), call.Err
+}
+
+// GetConnectivity gets the connectivity of this network connection.
+func (nc *interceptedNetworkConnection) GetConnectivity() (NLMConnectivity, error) {
+	call := nc.call("GetConnectivity", func(call *Call) {
+		connectivity, err := nc.conn.GetConnectivity()
+		call.Results, call.Err = []any{connectivity}, err
+	})
+	return callResult[NLMConnectivity](call, 0), call.Err
+}
+
+// GetConnectionId gets the unique identifier of this network connection.
+func (nc *interceptedNetworkConnection) GetConnectionId() (GUID, error) {
+	call := nc.call("GetConnectionId", func(call *Call) {
+		id, err := nc.conn.GetConnectionId()
+		call.Results, call.Err = []any{id}, err
+	})
+	return callResult[GUID](call, 0), call.Err
+}
+
+// GetAdapterId gets the unique identifier of the network adapter of this network connection.
+func (nc *interceptedNetworkConnection) GetAdapterId() (GUID, error) {
+	call := nc.call("GetAdapterId", func(call *Call) {
+		id, err := nc.conn.GetAdapterId()
+		call.Results, call.Err = []any{id}, err
+	})
+	return callResult[GUID](call, 0), call.Err
+}
+
+// GetDomainType gets the domain type of this network connection.
+func (nc *interceptedNetworkConnection) GetDomainType() (NLMDomainType, error) {
+	call := nc.call("GetDomainType", func(call *Call) {
+		domainType, err := nc.conn.GetDomainType()
+		call.Results, call.Err = []any{domainType}, err
+	})
+	return callResult[NLMDomainType](call, 0), call.Err
+}
+
+// Release releases the INetworkConnection object.
+func (nc *interceptedNetworkConnection) Release() {
+	nc.call("Release", func(*Call) { nc.conn.Release() })
+}
+
+// ForEach iterates over each INetworkConnection represented by IEnumNetworkConnections.
+// Only getting the network connections is intercepted, whose result is the slice of
+// INetworkConnection objects iterated over: do is called once the call completes, so
+// it neither counts towards the Duration of the call nor runs again on retries.
+func (nc *interceptedEnumNetworkConnections) ForEach(do func(int, INetworkConnection) bool) {
+	call := intercept(nc.chain, "IEnumNetworkConnections", "ForEach", nc.enum, nil, func(call *Call) {
+		call.Results = []any{nc.conns}
+	})
+	for i, conn := range callResult[[]INetworkConnection](call, 0) {
+		if keepGoing := do(i, conn); !keepGoing {
+			return
+		}
+	}
+}
+
+// Size returns the number of INetworkConnection objects in the IEnumNetworkConnections.
+func (nc *interceptedEnumNetworkConnections) Size() int {
+	call := intercept(nc.chain, "IEnumNetworkConnections", "Size", nc.enum, nil, func(call *Call) {
+		call.Results = []any{nc.enum.Size()}
+	})
+	return callResult[int](call, 0)
+}
+
+// Release releases the IEnumNetworkConnections object.
+func (nc *interceptedEnumNetworkConnections) Release() {
+	intercept(nc.chain, "IEnumNetworkConnections", "Release", nc.enum, nil, func(*Call) { nc.enum.Release() })
+}
@@ -0,0 +1,192 @@
+package wnlm_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+// recorder is an Interceptor which records the calls it sees.
+type recorder struct {
+	mu    sync.Mutex
+	calls []wnlm.Call
+}
+
+func (r *recorder) intercept(call *wnlm.Call, next wnlm.Invoker) {
+	next(call)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, *call)
+}
+
+// methods returns the "Interface.Method" of each recorded call.
+func (r *recorder) methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	methods := []string{}
+	for _, call := range r.calls {
+		methods = append(methods, call.Interface+"."+call.Method)
+	}
+	return methods
+}
+
+func TestInterceptNetworkListManager(t *testing.T) {
+	rec := &recorder{}
+	nlm := wnlm.InterceptNetworkListManager(fake.NewSample(), rec.intercept)
+
+	conns, conn := firstConnection(t, nlm)
+	network, err := conn.GetNetwork()
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	if name, err := network.GetName(); err != nil || name != "Home" {
+		t.Errorf("GetName = %q, %v, want Home", name, err)
+	}
+	network.Release()
+	conns.Release()
+	nlm.Release()
+
+	want := []string{
+		"INetworkListManager.GetNetworkConnections",
+		"IEnumNetworkConnections.ForEach",
+		"INetworkConnection.GetNetwork",
+		"INetwork.GetName",
+		"INetwork.Release",
+		"IEnumNetworkConnections.Release",
+		"INetworkListManager.Release",
+	}
+	if got := rec.methods(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	if result := rec.calls[3].Results; len(result) != 1 || result[0] != "Home" {
+		t.Errorf("GetName results = %v, want [Home]", result)
+	}
+}
+
+func TestChainInterceptors(t *testing.T) {
+	var order []string
+	interceptor := func(name string) wnlm.Interceptor {
+		return func(call *wnlm.Call, next wnlm.Invoker) {
+			order = append(order, name+" before")
+			next(call)
+			order = append(order, name+" after")
+		}
+	}
+	nlm := wnlm.InterceptNetworkListManager(fake.NewSample(), interceptor("outer"), interceptor("inner"))
+	if _, err := nlm.GetNetworkConnections(); err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+	if want := []string{"outer before", "inner before", "inner after", "outer after"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestInterceptorRewrites(t *testing.T) {
+	m := fake.NewSample()
+	nlm := wnlm.InterceptNetworkListManager(m, func(call *wnlm.Call, next wnlm.Invoker) {
+		if call.Method == "SetName" {
+			call.Args[0] = "Rewritten " + call.Args[0].(string)
+		}
+		next(call)
+		if call.Method == "GetDescription" {
+			call.Results[0] = "Intercepted"
+		}
+		if call.Method == "GetCategory" {
+			call.Results[0] = "not a category"
+		}
+	})
+	network, err := wnlm.FindNetwork(nlm, fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("FindNetwork failed: %v", err)
+	}
+	defer network.Release()
+
+	if err := network.SetName("Name"); err != nil {
+		t.Fatalf("SetName failed: %v", err)
+	}
+	if state, _ := m.Network(fake.SampleHomeID); state.Name != "Rewritten Name" {
+		t.Errorf("name = %q, want the rewritten argument", state.Name)
+	}
+	if descr, err := network.GetDescription(); err != nil || descr != "Intercepted" {
+		t.Errorf("GetDescription = %q, %v, want the rewritten result", descr, err)
+	}
+	// results of the wrong type are returned as zero values
+	if category, err := network.GetCategory(); err != nil || category != wnlm.NLMNetworkCategoryPublic {
+		t.Errorf("GetCategory = %s, %v, want the zero value", category, err)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	m := fake.NewSample()
+	denied := errors.New("denied")
+	nlm := wnlm.InterceptNetworkListManager(m, func(call *wnlm.Call, next wnlm.Invoker) {
+		if call.Method == "SetName" {
+			call.Err = denied
+			return
+		}
+		next(call)
+	})
+	network, err := wnlm.FindNetwork(nlm, fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("FindNetwork failed: %v", err)
+	}
+	defer network.Release()
+
+	if err := network.SetName("Name"); !errors.Is(err, denied) {
+		t.Errorf("SetName error = %v, want %v", err, denied)
+	}
+	if state, _ := m.Network(fake.SampleHomeID); state.Name != "Home" {
+		t.Errorf("name = %q, want it unchanged", state.Name)
+	}
+}
+
+func TestInterceptorForEach(t *testing.T) {
+	var forEach *wnlm.Call
+	nlm := wnlm.InterceptNetworkListManager(fake.NewSample(), func(call *wnlm.Call, next wnlm.Invoker) {
+		// retry every call once
+		next(call)
+		next(call)
+		if call.Method == "ForEach" {
+			forEach = call
+		}
+	})
+	conns, err := nlm.GetNetworkConnections()
+	if err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+	defer conns.Release()
+
+	const callbackTime = 50 * time.Millisecond
+	visited := 0
+	conns.ForEach(func(_ int, conn wnlm.INetworkConnection) bool {
+		visited++
+		time.Sleep(callbackTime)
+		return true
+	})
+	if visited != 2 {
+		t.Errorf("callback ran %d times, want once per connection", visited)
+	}
+	if forEach == nil {
+		t.Fatalf("ForEach was not intercepted")
+	}
+	if forEach.Duration >= callbackTime {
+		t.Errorf("ForEach Duration = %s, which includes the callback", forEach.Duration)
+	}
+
+	visited = 0
+	conns.ForEach(func(int, wnlm.INetworkConnection) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("callback ran %d times after returning false, want once", visited)
+	}
+	if conns.Size() != 2 {
+		t.Errorf("Size() = %d, want 2", conns.Size())
+	}
+}
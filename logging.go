@@ -0,0 +1,209 @@
+package wnlm
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// LogOption configures an Interceptor created with NewLogInterceptor.
+type LogOption func(*logOptions)
+
+// logOptions holds the configuration set by LogOptions.
+type logOptions struct {
+	level      slog.Level
+	errorLevel slog.Level
+	redact     bool
+}
+
+// WithLogLevel sets the level at which successful calls are logged. The default is slog.LevelDebug.
+func WithLogLevel(level slog.Level) LogOption {
+	return func(o *logOptions) { o.level = level }
+}
+
+// WithLogErrorLevel sets the level at which failed calls are logged. The default is slog.LevelWarn.
+func WithLogErrorLevel(level slog.Level) LogOption {
+	return func(o *logOptions) { o.errorLevel = level }
+}
+
+// WithRedactedNames replaces network names and descriptions in logs with a short hash
+// of their value, such that they can still be told apart without being disclosed. So
+// does the error of failed calls to get or set them, which may include the value.
+func WithRedactedNames() LogOption {
+	return func(o *logOptions) { o.redact = true }
+}
+
+// logInterceptor logs calls to a slog.Logger.
+type logInterceptor struct {
+	logger  *slog.Logger
+	options logOptions
+	// ids holds the GUID of each INetwork and INetworkConnection seen, until released
+	// (either on its own, or along with the enumeration it was obtained from).
+	ids sync.Map
+}
+
+// NewLogInterceptor returns an Interceptor (see InterceptNetworkListManager) which logs
+// every call with its interface and method, the GUID of the network or network connection
+// it was made on, its arguments, its (decoded) results and duration, and its error and
+// HRESULT if it failed. Finding the GUID of an object may take an extra call the first
+// time one of its methods is logged.
+func NewLogInterceptor(logger *slog.Logger, opts ...LogOption) Interceptor {
+	options := logOptions{level: slog.LevelDebug, errorLevel: slog.LevelWarn}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	l := &logInterceptor{logger: logger, options: options}
+	return l.intercept
+}
+
+// intercept implements Interceptor.
+func (l *logInterceptor) intercept(call *Call, next Invoker) {
+	if call.Method == "Release" {
+		released := releasedObjects(call.Target)
+		defer func() {
+			for _, obj := range released {
+				l.ids.Delete(obj)
+			}
+		}()
+	}
+	next(call)
+
+	level := l.options.level
+	if call.Err != nil {
+		level = l.options.errorLevel
+	}
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("interface", call.Interface),
+		slog.String("method", call.Method),
+	}
+	if key, id, ok := l.targetID(call); ok {
+		attrs = append(attrs, slog.String(key, id.String()))
+	}
+	if len(call.Args) > 0 {
+		attrs = append(attrs, slog.Any("args", l.decode(call.Method, call.Args)))
+	}
+	if call.Err != nil {
+		errText := call.Err.Error()
+		if l.redacts(call.Method) {
+			errText = redact(errText)
+		}
+		attrs = append(attrs, slog.String("error", errText))
+		if hr, ok := hresult.FromError(call.Err); ok {
+			attrs = append(attrs, slog.String("hresult", fmt.Sprintf("0x%08X", uint32(hr))))
+			if name := hr.Name(); name != "" {
+				attrs = append(attrs, slog.String("hresult_name", name))
+			}
+		}
+	} else if len(call.Results) > 0 {
+		attrs = append(attrs, slog.Any("result", l.decode(call.Method, call.Results)))
+	}
+	attrs = append(attrs, slog.Duration("duration", call.Duration))
+
+	l.logger.LogAttrs(ctx, level, "NLM call", attrs...)
+}
+
+// releasedObjects returns the objects released by releasing target: the target
+// itself and, for enumerations, each of their network connections.
+func releasedObjects(target any) []any {
+	released := []any{target}
+	if enum, ok := target.(IEnumNetworkConnections); ok && enum != nil {
+		enum.ForEach(func(_ int, conn INetworkConnection) bool {
+			released = append(released, conn)
+			return true
+		})
+	}
+	return released
+}
+
+// targetID returns the attribute key and GUID of the object a call was made on, if any.
+// The GUID is looked up once per object, and forgotten once the object is released.
+func (l *logInterceptor) targetID(call *Call) (string, GUID, bool) {
+	var key string
+	var lookup func() (GUID, error)
+	switch target := call.Target.(type) {
+	case INetwork:
+		key, lookup = "network", target.GetNetworkId
+	case INetworkConnection:
+		key, lookup = "connection", target.GetConnectionId
+	default:
+		return "", GUID{}, false
+	}
+	if id, ok := l.ids.Load(call.Target); ok {
+		return key, id.(GUID), true
+	}
+	if call.Method == "Release" {
+		// the object can no longer be called
+		return "", GUID{}, false
+	}
+	id, err := lookup()
+	if err != nil {
+		return "", GUID{}, false
+	}
+	l.ids.Store(call.Target, id)
+	return key, id, true
+}
+
+// decode returns the loggable form of the arguments or results of a call to the given method.
+func (l *logInterceptor) decode(method string, values []any) any {
+	decoded := make([]any, len(values))
+	for i, value := range values {
+		decoded[i] = l.decodeValue(method, value)
+	}
+	if len(decoded) == 1 {
+		return decoded[0]
+	}
+	return decoded
+}
+
+// decodeValue returns the loggable form of a single argument or result of a call to the given method.
+func (l *logInterceptor) decodeValue(method string, value any) any {
+	switch v := value.(type) {
+	case string:
+		if l.redacts(method) {
+			return redact(v)
+		}
+		return v
+	case time.Time:
+		return v
+	case IEnumNetworkConnections:
+		if v == nil {
+			return nil
+		}
+		return fmt.Sprintf("%d connection(s)", v.Size())
+	case []INetworkConnection:
+		return fmt.Sprintf("%d connection(s)", len(v))
+	case INetwork:
+		return "INetwork"
+	case INetworkConnection:
+		return "INetworkConnection"
+	case fmt.Stringer:
+		if str := v.String(); str != "" {
+			return str
+		}
+		return fmt.Sprintf("%d", value)
+	default:
+		return value
+	}
+}
+
+// redacts returns true if the values of calls to the given method are redacted.
+func (l *logInterceptor) redacts(method string) bool {
+	return l.options.redact && (strings.HasSuffix(method, "Name") || strings.HasSuffix(method, "Description"))
+}
+
+// redact returns a short hash of a value, which identifies but does not disclose it.
+func redact(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return fmt.Sprintf("redacted:%x", sum[:4])
+}
@@ -0,0 +1,68 @@
+package wnlm
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// stubConnection is an INetworkConnection with nothing but an ID.
+type stubConnection struct {
+	id GUID
+}
+
+func (c *stubConnection) GetNetwork() (INetwork, error)             { return nil, nil }
+func (c *stubConnection) IsConnectedToInternet() (bool, error)      { return false, nil }
+func (c *stubConnection) IsConnected() (bool, error)                { return false, nil }
+func (c *stubConnection) GetConnectivity() (NLMConnectivity, error) { return 0, nil }
+func (c *stubConnection) GetConnectionId() (GUID, error)            { return c.id, nil }
+func (c *stubConnection) GetAdapterId() (GUID, error)               { return GUID{}, nil }
+func (c *stubConnection) GetDomainType() (NLMDomainType, error)     { return 0, nil }
+func (c *stubConnection) Release()                                  {}
+
+// stubNetworkListManager is an INetworkListManager with two network connections.
+type stubNetworkListManager struct{}
+
+func (stubNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	return NewNetworkConnectionsFromSlice([]INetworkConnection{
+		&stubConnection{id: GUID{Data1: 1}},
+		&stubConnection{id: GUID{Data1: 2}},
+	}), nil
+}
+
+func (stubNetworkListManager) Release() {}
+
+func TestLogInterceptorForgetsReleasedObjects(t *testing.T) {
+	for _, level := range []slog.Level{slog.LevelDebug, slog.LevelError} {
+		l := &logInterceptor{
+			logger:  slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: level})),
+			options: logOptions{level: slog.LevelDebug, errorLevel: slog.LevelWarn},
+		}
+		nlm := InterceptNetworkListManager(stubNetworkListManager{}, l.intercept)
+		for i := 0; i < 10; i++ {
+			conns, err := nlm.GetNetworkConnections()
+			if err != nil {
+				t.Fatalf("GetNetworkConnections failed: %v", err)
+			}
+			conns.ForEach(func(_ int, conn INetworkConnection) bool {
+				conn.IsConnected()
+				return true
+			})
+			// connections are released along with their enumeration
+			conns.Release()
+		}
+		if n := len(knownIDs(l)); n != 0 {
+			t.Errorf("level %s: %d GUIDs still known once every object was released, want 0", level, n)
+		}
+	}
+}
+
+// knownIDs returns the GUIDs held by a logInterceptor.
+func knownIDs(l *logInterceptor) []GUID {
+	ids := []GUID{}
+	l.ids.Range(func(_, id any) bool {
+		ids = append(ids, id.(GUID))
+		return true
+	})
+	return ids
+}
@@ -0,0 +1,193 @@
+package wnlm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// captureHandler is a slog.Handler which captures the records it handles.
+type captureHandler struct {
+	level   slog.Level
+	mu      sync.Mutex
+	records []capturedRecord
+}
+
+// capturedRecord is a captured slog.Record, with its attributes resolved to strings.
+type capturedRecord struct {
+	level slog.Level
+	msg   string
+	attrs map[string]string
+}
+
+func (h *captureHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	record := capturedRecord{level: r.Level, msg: r.Message, attrs: map[string]string{}}
+	r.Attrs(func(attr slog.Attr) bool {
+		record.attrs[attr.Key] = attr.Value.Resolve().String()
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler      { return h }
+
+// find returns the captured record of the call to the given method.
+func (h *captureHandler) find(t *testing.T, method string) capturedRecord {
+	t.Helper()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, record := range h.records {
+		if record.attrs["method"] == method {
+			return record
+		}
+	}
+	t.Fatalf("no record of a call to %s", method)
+	return capturedRecord{}
+}
+
+// loggedNetwork returns the Home network of a fake whose calls are logged to h.
+func loggedNetwork(t *testing.T, h *captureHandler, opts ...wnlm.LogOption) wnlm.INetwork {
+	t.Helper()
+	m := fake.NewSample()
+	m.SetFault(func(iface, method string) error {
+		switch method {
+		case "SetName", "SetDescription":
+			return fmt.Errorf("failed to call %s method with value Secret Name: %w", method, hresult.E_ACCESSDENIED)
+		case "SetCategory":
+			return fmt.Errorf("failed to call %s method with value Private: %w", method, hresult.E_ACCESSDENIED)
+		}
+		return nil
+	})
+	nlm := wnlm.InterceptNetworkListManager(m, wnlm.NewLogInterceptor(slog.New(h), opts...))
+	t.Cleanup(nlm.Release)
+	network, err := wnlm.FindNetwork(nlm, fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("FindNetwork failed: %v", err)
+	}
+	t.Cleanup(network.Release)
+	return network
+}
+
+func TestLogInterceptor(t *testing.T) {
+	h := &captureHandler{level: slog.LevelDebug}
+	network := loggedNetwork(t, h)
+	if _, err := network.GetName(); err != nil {
+		t.Fatalf("GetName failed: %v", err)
+	}
+	if _, err := network.GetConnectivity(); err != nil {
+		t.Fatalf("GetConnectivity failed: %v", err)
+	}
+	if err := network.SetName("Secret Name"); !errors.Is(err, hresult.E_ACCESSDENIED) {
+		t.Fatalf("SetName error = %v, want E_ACCESSDENIED", err)
+	}
+
+	record := h.find(t, "GetName")
+	if record.level != slog.LevelDebug || record.msg != "NLM call" {
+		t.Errorf("GetName logged %q at %s", record.msg, record.level)
+	}
+	want := map[string]string{"interface": "INetwork", "method": "GetName", "network": fake.SampleHomeID.String(), "result": "Home"}
+	for key, value := range want {
+		if record.attrs[key] != value {
+			t.Errorf("GetName %s = %q, want %q", key, record.attrs[key], value)
+		}
+	}
+	if _, ok := record.attrs["duration"]; !ok {
+		t.Errorf("GetName logged without a duration")
+	}
+	if got := h.find(t, "GetConnectivity").attrs["result"]; got != "IPv4Internet, IPv6LocalNetwork" {
+		t.Errorf("GetConnectivity result = %q", got)
+	}
+
+	record = h.find(t, "SetName")
+	if record.level != slog.LevelWarn {
+		t.Errorf("SetName logged at %s, want WARN", record.level)
+	}
+	want = map[string]string{
+		"args":         "Secret Name",
+		"hresult":      "0x80070005",
+		"hresult_name": "E_ACCESSDENIED",
+	}
+	for key, value := range want {
+		if record.attrs[key] != value {
+			t.Errorf("SetName %s = %q, want %q", key, record.attrs[key], value)
+		}
+	}
+	if !strings.Contains(record.attrs["error"], "Secret Name") {
+		t.Errorf("SetName error = %q, want the error text", record.attrs["error"])
+	}
+}
+
+func TestLogInterceptorLevels(t *testing.T) {
+	h := &captureHandler{level: slog.LevelInfo}
+	network := loggedNetwork(t, h, wnlm.WithLogLevel(slog.LevelDebug), wnlm.WithLogErrorLevel(slog.LevelError))
+	if _, err := network.GetName(); err != nil {
+		t.Fatalf("GetName failed: %v", err)
+	}
+	_ = network.SetDescription("Secret Name")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.records) != 1 || h.records[0].attrs["method"] != "SetDescription" || h.records[0].level != slog.LevelError {
+		t.Errorf("records = %+v, want only the failed SetDescription at ERROR", h.records)
+	}
+}
+
+func TestLogInterceptorRedactedNames(t *testing.T) {
+	h := &captureHandler{level: slog.LevelDebug}
+	network := loggedNetwork(t, h, wnlm.WithRedactedNames())
+	if _, err := network.GetName(); err != nil {
+		t.Fatalf("GetName failed: %v", err)
+	}
+	if _, err := network.GetDescription(); err != nil {
+		t.Fatalf("GetDescription failed: %v", err)
+	}
+	_ = network.SetName("Secret Name")
+	_ = network.SetDescription("Secret Name")
+	_ = network.SetCategory(wnlm.NLMNetworkCategoryPrivate)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, record := range h.records {
+		for key, value := range record.attrs {
+			if strings.Contains(value, "Home") || strings.Contains(value, "Secret") {
+				t.Errorf("%s %s = %q discloses a name or description", record.attrs["method"], key, value)
+			}
+		}
+	}
+	for _, record := range h.records {
+		switch record.attrs["method"] {
+		case "GetName":
+			if !strings.HasPrefix(record.attrs["result"], "redacted:") {
+				t.Errorf("GetName result = %q, want it redacted", record.attrs["result"])
+			}
+		case "SetName":
+			if !strings.HasPrefix(record.attrs["args"], "redacted:") || !strings.HasPrefix(record.attrs["error"], "redacted:") {
+				t.Errorf("SetName args = %q and error = %q, want them redacted", record.attrs["args"], record.attrs["error"])
+			}
+			if record.attrs["hresult_name"] != "E_ACCESSDENIED" {
+				t.Errorf("SetName hresult_name = %q, want E_ACCESSDENIED", record.attrs["hresult_name"])
+			}
+		case "SetCategory":
+			// only names and descriptions are redacted
+			if record.attrs["args"] != "Private" || !strings.Contains(record.attrs["error"], "SetCategory") {
+				t.Errorf("SetCategory args = %q and error = %q, want them as is", record.attrs["args"], record.attrs["error"])
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package wnlm
+
+// ManagerOption configures the INetworkListManager returned by NewNetworkListManager.
+type ManagerOption func(*managerOptions)
+
+// managerOptions holds the configuration set by ManagerOptions.
+type managerOptions struct {
+	preResolveDISPIDs bool
+}
+
+// newManagerOptions returns the managerOptions resulting from applying opts.
+func newManagerOptions(opts ...ManagerOption) managerOptions {
+	options := managerOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithPreResolvedDISPIDs makes the INetworkListManager, and every INetwork and
+// INetworkConnection obtained from it, resolve the IDispatch DISPIDs of their
+// members when they are constructed rather than on first use. DISPIDs are cached
+// per interface and shared by all objects, so only the first construction of
+// each interface pays for the resolution.
+func WithPreResolvedDISPIDs() ManagerOption {
+	return func(o *managerOptions) { o.preResolveDISPIDs = true }
+}
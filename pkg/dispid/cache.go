@@ -0,0 +1,94 @@
+package dispid
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-ole/go-ole"
+)
+
+// Resolver resolves the dispatch identifier (DISPID) of a member of an object
+// by name, such as (*ole.IDispatch).GetSingleIDOfName.
+type Resolver func(name string) (int32, error)
+
+// key identifies a member of a COM interface. IDispatch member names are case
+// insensitive so names are stored in lower case.
+type key struct {
+	iid  ole.GUID
+	name string
+}
+
+// Cache is a concurrency-safe cache of DISPIDs keyed by interface GUID and
+// member name. Since DISPIDs are fixed for a given interface, a single Cache
+// can be shared across all objects implementing the same interface.
+//
+// Entries are populated lazily on lookup, or eagerly with Resolve.
+type Cache struct {
+	mu      sync.RWMutex
+	dispids map[key]int32
+}
+
+// Default is the Cache shared by all objects of this module.
+var Default = NewCache()
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{dispids: make(map[key]int32)}
+}
+
+// Lookup returns the DISPID of the named member of the interface with the given
+// GUID, calling resolve (and caching its result) if it is not cached already.
+// Failed resolutions are not cached.
+//
+// Concurrent lookups of the same uncached member may each call resolve,
+// which is harmless since resolution is idempotent.
+func (c *Cache) Lookup(iid ole.GUID, name string, resolve Resolver) (int32, error) {
+	k := key{iid: iid, name: strings.ToLower(name)}
+
+	c.mu.RLock()
+	dispid, ok := c.dispids[k]
+	c.mu.RUnlock()
+	if ok {
+		return dispid, nil
+	}
+
+	dispid, err := resolve(name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve DISPID of member %s: %w", name, err)
+	}
+
+	c.mu.Lock()
+	c.dispids[k] = dispid
+	c.mu.Unlock()
+	return dispid, nil
+}
+
+// Resolve eagerly resolves and caches the DISPIDs of the named members
+// of the interface with the given GUID which are not cached already.
+func (c *Cache) Resolve(iid ole.GUID, names []string, resolve Resolver) error {
+	for _, name := range names {
+		if _, err := c.Lookup(iid, name, resolve); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of cached DISPIDs.
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.dispids)
+}
+
+// Forget removes all cached DISPIDs of the interface with the given GUID.
+func (c *Cache) Forget(iid ole.GUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k := range c.dispids {
+		if k.iid == iid {
+			delete(c.dispids, k)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package dispid
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-ole/go-ole"
+)
+
+var (
+	iidA = *ole.NewGUID("{DCB00002-570F-4A9B-8D69-199FDBA5723B}")
+	iidB = *ole.NewGUID("{DCB00005-570F-4A9B-8D69-199FDBA5723B}")
+)
+
+// counter returns a Resolver which resolves names to their length and counts its calls.
+func counter(calls *atomic.Int32) Resolver {
+	return func(name string) (int32, error) {
+		calls.Add(1)
+		return int32(len(name)), nil
+	}
+}
+
+func TestCacheLookup(t *testing.T) {
+	c := NewCache()
+	var calls atomic.Int32
+	for _, name := range []string{"GetName", "getname", "GETNAME"} {
+		dispid, err := c.Lookup(iidA, name, counter(&calls))
+		if err != nil || dispid != 7 {
+			t.Errorf("Lookup(%q) = %d, %v, want 7", name, dispid, err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("resolved %d times, want once", calls.Load())
+	}
+	if _, err := c.Lookup(iidB, "GetName", counter(&calls)); err != nil || calls.Load() != 2 {
+		t.Errorf("Lookup of another interface's member resolved %d times, %v", calls.Load(), err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestCacheLookupFailure(t *testing.T) {
+	c := NewCache()
+	unknown := errors.New("unknown name")
+	_, err := c.Lookup(iidA, "Nope", func(string) (int32, error) { return 0, unknown })
+	if !errors.Is(err, unknown) {
+		t.Errorf("Lookup error = %v, want %v", err, unknown)
+	}
+	if c.Len() != 0 {
+		t.Errorf("failed resolution was cached")
+	}
+}
+
+func TestCacheResolveAndForget(t *testing.T) {
+	c := NewCache()
+	var calls atomic.Int32
+	if err := c.Resolve(iidA, []string{"GetName", "SetName", "GetName"}, counter(&calls)); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if err := c.Resolve(iidB, []string{"GetNetwork"}, counter(&calls)); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if calls.Load() != 3 || c.Len() != 3 {
+		t.Errorf("resolved %d times and cached %d DISPIDs, want 3 and 3", calls.Load(), c.Len())
+	}
+
+	failing := func(name string) (int32, error) {
+		if name == "Bad" {
+			return 0, errors.New("bad")
+		}
+		return 1, nil
+	}
+	if err := c.Resolve(iidA, []string{"Good", "Bad", "Never"}, failing); err == nil {
+		t.Errorf("Resolve succeeded despite a failing member")
+	}
+	if c.Len() != 4 {
+		t.Errorf("Len() = %d, want 4 (members before the failure are cached)", c.Len())
+	}
+
+	c.Forget(iidA)
+	if c.Len() != 1 {
+		t.Errorf("Len() after Forget = %d, want 1", c.Len())
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	c := NewCache()
+	var calls atomic.Int32
+	names := []string{"GetName", "SetName", "GetDescription", "SetDescription", "GetCategory"}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				name := names[(g+i)%len(names)]
+				iid := iidA
+				if i%2 == 0 {
+					iid = iidB
+				}
+				dispid, err := c.Lookup(iid, name, counter(&calls))
+				if err != nil || dispid != int32(len(name)) {
+					t.Errorf("Lookup(%q) = %d, %v", name, dispid, err)
+					return
+				}
+				if i%50 == 0 {
+					c.Len()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Len() != 2*len(names) {
+		t.Errorf("Len() = %d, want %d", c.Len(), 2*len(names))
+	}
+	// concurrent misses of the same member may each resolve it, but cached members never are
+	if calls.Load() < int32(2*len(names)) {
+		t.Errorf("resolved %d times, want at least %d", calls.Load(), 2*len(names))
+	}
+	before := calls.Load()
+	for _, name := range names {
+		c.Lookup(iidA, name, counter(&calls))
+	}
+	if calls.Load() != before {
+		t.Errorf("cached members were resolved again")
+	}
+}
+
+func TestCacheConcurrentForget(t *testing.T) {
+	c := NewCache()
+	var calls atomic.Int32
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if err := c.Resolve(iidA, []string{fmt.Sprintf("Member%d", i%10)}, counter(&calls)); err != nil {
+					t.Errorf("Resolve failed: %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				c.Forget(iidA)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Len() > 10 {
+		t.Errorf("Len() = %d, want at most 10", c.Len())
+	}
+}
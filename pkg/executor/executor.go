@@ -0,0 +1,242 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrClosed is returned when submitting work to a closed Executor.
+	ErrClosed = errors.New("executor closed")
+)
+
+// PanicError is the value with which Do (or New, or Close) panics when the
+// function it ran on the Executor panicked. It holds the original panic value
+// along with the stack trace of the Executor's goroutine at the time of the panic.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic on executor thread: %v\n\n%s", p.Value, p.Stack)
+}
+
+// Unwrap returns the original panic value if it was an error.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// call is a unit of work submitted to an Executor.
+type call struct {
+	fn     func() error
+	result chan result
+}
+
+// result is the outcome of a call.
+type result struct {
+	err      error
+	panicErr *PanicError
+}
+
+// Executor runs functions one at a time, in submission order, on a single
+// goroutine which is locked to its OS thread for the Executor's lifetime.
+//
+// This is what thread-affine APIs such as COM require: the thread is set up
+// (e.g. with CoInitializeEx) when the Executor starts, torn down (e.g. with
+// CoUninitialize) when it is closed, and all work in between is marshalled
+// onto it regardless of which goroutine submits it.
+type Executor struct {
+	calls chan call
+	quit  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+
+	mu        sync.Mutex
+	owned     map[uint64]func()
+	nextOwned uint64
+	closed    bool
+	closeErr  *PanicError
+}
+
+// New starts a new Executor, running setup on its thread before accepting any
+// work. If setup fails the Executor is stopped and the error is returned, and
+// if setup panics New panics with a *PanicError in the calling goroutine.
+// Otherwise teardown (if not nil) runs on the Executor's thread when it is closed.
+func New(setup func() error, teardown func()) (*Executor, error) {
+	e := &Executor{
+		calls: make(chan call),
+		quit:  make(chan struct{}),
+		done:  make(chan struct{}),
+		owned: make(map[uint64]func()),
+	}
+	started := make(chan result, 1)
+	go e.run(setup, teardown, started)
+	res := <-started
+	if res.panicErr != nil {
+		panic(res.panicErr)
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	return e, nil
+}
+
+// run is the body of the Executor's goroutine.
+func (e *Executor) run(setup func() error, teardown func(), started chan<- result) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(e.done)
+
+	if setup != nil {
+		if res := execute(setup); res.err != nil || res.panicErr != nil {
+			started <- res
+			return
+		}
+	}
+	started <- result{}
+
+	for {
+		select {
+		case c := <-e.calls:
+			c.result <- execute(c.fn)
+		case <-e.quit:
+			e.shutdown(teardown)
+			return
+		}
+	}
+}
+
+// shutdown runs the releases of all objects still owned by the Executor, most
+// recently owned first, followed by teardown. Panics are recovered so that the
+// remaining releases and teardown still run, and the first one is kept for Close.
+func (e *Executor) shutdown(teardown func()) {
+	e.mu.Lock()
+	e.closed = true
+	ids := make([]uint64, 0, len(e.owned))
+	for id := range e.owned {
+		ids = append(ids, id)
+	}
+	releases := e.owned
+	e.owned = nil
+	e.mu.Unlock()
+
+	fns := make([]func() error, 0, len(ids)+1)
+	slices.Sort(ids)
+	for _, id := range slices.Backward(ids) {
+		release := releases[id]
+		fns = append(fns, func() error { release(); return nil })
+	}
+	if teardown != nil {
+		fns = append(fns, func() error { teardown(); return nil })
+	}
+	for _, fn := range fns {
+		if res := execute(fn); res.panicErr != nil && e.closeErr == nil {
+			e.closeErr = res.panicErr
+		}
+	}
+}
+
+// Own registers release (e.g. that of a COM object bound to the Executor's thread)
+// to run on the Executor's thread when the Executor is closed, and returns a
+// function which runs it on the Executor's thread now instead. Either way release
+// runs at most once, so objects which are only released after the Executor is
+// closed are not leaked: they were already released by Close, and releasing them
+// again is a no-op.
+//
+// Own must be called before the Executor is closed, which is guaranteed when it
+// is called by a function running on the Executor. Otherwise release never runs.
+func (e *Executor) Own(release func()) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return func() {}
+	}
+	id := e.nextOwned
+	e.nextOwned++
+	e.owned[id] = release
+	return func() {
+		_ = e.Do(func() error {
+			e.mu.Lock()
+			release, ok := e.owned[id]
+			delete(e.owned, id)
+			e.mu.Unlock()
+			if ok {
+				release()
+			}
+			return nil
+		})
+	}
+}
+
+// execute runs fn, recovering from (and reporting) any panics.
+func execute(fn func() error) (res result) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = result{panicErr: &PanicError{Value: r, Stack: debug.Stack()}}
+		}
+	}()
+	return result{err: fn()}
+}
+
+// Do runs fn on the Executor's thread and returns its error, blocking until it
+// completes. If fn panics, Do panics with a *PanicError in the calling goroutine,
+// and the Executor keeps running. ErrClosed is returned if the Executor is closed.
+//
+// fn must not call Do on the same Executor, since that would deadlock.
+func (e *Executor) Do(fn func() error) error {
+	c := call{fn: fn, result: make(chan result, 1)}
+	select {
+	case e.calls <- c:
+	case <-e.done:
+		return ErrClosed
+	case <-e.quit:
+		return ErrClosed
+	}
+	res := <-c.result
+	if res.panicErr != nil {
+		panic(res.panicErr)
+	}
+	return res.err
+}
+
+// Call runs fn on the Executor's thread and returns its results, with the same
+// semantics as Do.
+func Call[T any](e *Executor, fn func() (T, error)) (T, error) {
+	var value T
+	err := e.Do(func() error {
+		var err error
+		value, err = fn()
+		return err
+	})
+	return value, err
+}
+
+// Close stops the Executor, waiting for the function currently running (if
+// any) to complete, for the objects it still owns to be released and for
+// teardown to run. Functions submitted after Close fail with ErrClosed. If any
+// release or teardown panicked, Close panics with a *PanicError for the first
+// of them once all have run. Close is safe to call multiple times.
+func (e *Executor) Close() {
+	e.once.Do(func() { close(e.quit) })
+	<-e.done
+	if e.closeErr != nil {
+		panic(e.closeErr)
+	}
+}
+
+// Closed returns true if the Executor has been closed.
+func (e *Executor) Closed() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,226 @@
+package executor
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// mustPanic runs fn and returns the *PanicError it panicked with.
+func mustPanic(t *testing.T, fn func()) (panicErr *PanicError) {
+	t.Helper()
+	defer func() {
+		var ok bool
+		if panicErr, ok = recover().(*PanicError); !ok {
+			t.Fatalf("did not panic with a *PanicError")
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestExecutorOrdering(t *testing.T) {
+	var order []string
+	e, err := New(
+		func() error { order = append(order, "setup"); return nil },
+		func() { order = append(order, "teardown") },
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if err := e.Do(func() error { order = append(order, name); return nil }); err != nil {
+			t.Fatalf("Do failed: %v", err)
+		}
+	}
+	e.Close()
+	if want := []string{"setup", "a", "b", "c", "teardown"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestExecutorSerializes(t *testing.T) {
+	e, err := New(nil, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer e.Close()
+
+	// the counter is deliberately unsynchronized: the race detector
+	// flags it unless the Executor runs one function at a time.
+	counter := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := Call(e, func() (int, error) { counter++; return counter, nil })
+			if err != nil || value == 0 {
+				t.Errorf("Call = %d, %v", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n, _ := Call(e, func() (int, error) { return counter, nil }); n != 50 {
+		t.Errorf("counter = %d, want 50", n)
+	}
+}
+
+func TestExecutorErrors(t *testing.T) {
+	setupErr := errors.New("setup failed")
+	tornDown := false
+	if _, err := New(func() error { return setupErr }, func() { tornDown = true }); !errors.Is(err, setupErr) {
+		t.Errorf("New error = %v, want %v", err, setupErr)
+	}
+	if tornDown {
+		t.Errorf("teardown ran after setup failed")
+	}
+
+	e, err := New(nil, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer e.Close()
+	fnErr := errors.New("fn failed")
+	if err := e.Do(func() error { return fnErr }); !errors.Is(err, fnErr) {
+		t.Errorf("Do error = %v, want %v", err, fnErr)
+	}
+	if value, err := Call(e, func() (string, error) { return "value", nil }); err != nil || value != "value" {
+		t.Errorf("Call = %q, %v", value, err)
+	}
+}
+
+func TestExecutorClose(t *testing.T) {
+	teardowns := 0
+	e, err := New(nil, func() { teardowns++ })
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if e.Closed() {
+		t.Errorf("Closed() = true before Close")
+	}
+
+	started, finished := make(chan struct{}), make(chan struct{})
+	go func() {
+		_ = e.Do(func() error {
+			close(started)
+			<-finished
+			return nil
+		})
+	}()
+	<-started
+	closed := make(chan struct{})
+	go func() {
+		e.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatalf("Close returned while a function was running")
+	default:
+	}
+	close(finished)
+	<-closed
+
+	e.Close()
+	if !e.Closed() || teardowns != 1 {
+		t.Errorf("Closed() = %t with %d teardowns, want true and 1", e.Closed(), teardowns)
+	}
+	if err := e.Do(func() error { t.Errorf("function ran after Close"); return nil }); !errors.Is(err, ErrClosed) {
+		t.Errorf("Do after Close error = %v, want ErrClosed", err)
+	}
+	if _, err := Call(e, func() (int, error) { return 1, nil }); !errors.Is(err, ErrClosed) {
+		t.Errorf("Call after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestExecutorPanics(t *testing.T) {
+	e, err := New(nil, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer e.Close()
+
+	cause := errors.New("cause")
+	panicErr := mustPanic(t, func() { _ = e.Do(func() error { panic(cause) }) })
+	if !errors.Is(panicErr, cause) || len(panicErr.Stack) == 0 {
+		t.Errorf("Do panicked with %v, want %v with a stack", panicErr, cause)
+	}
+	panicErr = mustPanic(t, func() { _, _ = Call(e, func() (int, error) { panic("boom") }) })
+	if panicErr.Value != "boom" || panicErr.Unwrap() != nil {
+		t.Errorf("Call panicked with %v, want boom", panicErr.Value)
+	}
+	if err := e.Do(func() error { return nil }); err != nil {
+		t.Errorf("Do after a panic failed: %v", err)
+	}
+}
+
+func TestExecutorSetupPanics(t *testing.T) {
+	tornDown := false
+	panicErr := mustPanic(t, func() {
+		_, _ = New(func() error { panic("setup") }, func() { tornDown = true })
+	})
+	if panicErr.Value != "setup" {
+		t.Errorf("New panicked with %v, want setup", panicErr.Value)
+	}
+	if tornDown {
+		t.Errorf("teardown ran after setup panicked")
+	}
+}
+
+func TestExecutorOwn(t *testing.T) {
+	var released []string
+	e, err := New(nil, func() { released = append(released, "teardown") })
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	releaseA := e.Own(func() { released = append(released, "a") })
+	releaseB := e.Own(func() { released = append(released, "b") })
+	releaseC, err := Call(e, func() (func(), error) {
+		return e.Own(func() { released = append(released, "c") }), nil
+	})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	releaseB()
+	releaseB()
+	if !slices.Equal(released, []string{"b"}) {
+		t.Errorf("released = %v, want [b]", released)
+	}
+
+	// objects still owned on Close are released before teardown, most recent first
+	e.Close()
+	if want := []string{"b", "c", "a", "teardown"}; !slices.Equal(released, want) {
+		t.Errorf("released = %v, want %v", released, want)
+	}
+	releaseA()
+	releaseC()
+	if len(released) != 4 {
+		t.Errorf("releasing after Close released again: %v", released)
+	}
+	e.Own(func() { t.Errorf("released an object owned after Close") })()
+}
+
+func TestExecutorClosePanics(t *testing.T) {
+	var released []string
+	e, err := New(nil, func() { released = append(released, "teardown") })
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	e.Own(func() { released = append(released, "a") })
+	e.Own(func() { panic("release") })
+	e.Own(func() { released = append(released, "c") })
+
+	panicErr := mustPanic(t, e.Close)
+	if panicErr.Value != "release" {
+		t.Errorf("Close panicked with %v, want release", panicErr.Value)
+	}
+	if want := []string{"c", "a", "teardown"}; !slices.Equal(released, want) {
+		t.Errorf("released = %v, want %v", released, want)
+	}
+	if !e.Closed() {
+		t.Errorf("Closed() = false after Close panicked")
+	}
+}
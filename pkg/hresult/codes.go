@@ -0,0 +1,124 @@
+package hresult
+
+// Well-known HRESULT values. Names follow the Windows SDK definitions, and
+// failure values are offset by 1<<32 to fit the signed HRESULT type.
+const (
+	// S_OK represents success.
+	S_OK = HRESULT(0x00000000)
+	// S_FALSE represents success with a false/negative result.
+	S_FALSE = HRESULT(0x00000001)
+
+	// E_NOTIMPL represents a method that is not implemented.
+	E_NOTIMPL = HRESULT(0x80004001 - 1<<32)
+	// E_NOINTERFACE represents an interface that is not supported.
+	E_NOINTERFACE = HRESULT(0x80004002 - 1<<32)
+	// E_POINTER represents an invalid pointer.
+	E_POINTER = HRESULT(0x80004003 - 1<<32)
+	// E_ABORT represents an aborted operation.
+	E_ABORT = HRESULT(0x80004004 - 1<<32)
+	// E_FAIL represents an unspecified failure.
+	E_FAIL = HRESULT(0x80004005 - 1<<32)
+	// E_UNEXPECTED represents an unexpected failure.
+	E_UNEXPECTED = HRESULT(0x8000FFFF - 1<<32)
+	// E_ACCESSDENIED represents a general access denied error.
+	E_ACCESSDENIED = HRESULT(0x80070005 - 1<<32)
+	// E_HANDLE represents an invalid handle.
+	E_HANDLE = HRESULT(0x80070006 - 1<<32)
+	// E_OUTOFMEMORY represents a failure to allocate memory.
+	E_OUTOFMEMORY = HRESULT(0x8007000E - 1<<32)
+	// E_INVALIDARG represents one or more invalid arguments.
+	E_INVALIDARG = HRESULT(0x80070057 - 1<<32)
+	// E_NOT_FOUND represents an element that was not found (ERROR_NOT_FOUND).
+	E_NOT_FOUND = HRESULT(0x80070490 - 1<<32)
+	// E_ELEVATION_REQUIRED represents an operation that requires elevation (ERROR_ELEVATION_REQUIRED).
+	E_ELEVATION_REQUIRED = HRESULT(0x800702E4 - 1<<32)
+
+	// CLASS_E_NOAGGREGATION represents a class that does not support aggregation.
+	CLASS_E_NOAGGREGATION = HRESULT(0x80040110 - 1<<32)
+	// REGDB_E_CLASSNOTREG represents a class that is not registered.
+	REGDB_E_CLASSNOTREG = HRESULT(0x80040154 - 1<<32)
+	// CO_E_NOTINITIALIZED represents a call made before CoInitialize.
+	CO_E_NOTINITIALIZED = HRESULT(0x800401F0 - 1<<32)
+	// CO_E_ALREADYINITIALIZED represents a repeated call to CoInitialize.
+	CO_E_ALREADYINITIALIZED = HRESULT(0x800401F1 - 1<<32)
+
+	// DISP_E_MEMBERNOTFOUND represents an IDispatch member that was not found.
+	DISP_E_MEMBERNOTFOUND = HRESULT(0x80020003 - 1<<32)
+	// DISP_E_TYPEMISMATCH represents an IDispatch argument of the wrong type.
+	DISP_E_TYPEMISMATCH = HRESULT(0x80020005 - 1<<32)
+	// DISP_E_UNKNOWNNAME represents an unknown IDispatch member name.
+	DISP_E_UNKNOWNNAME = HRESULT(0x80020006 - 1<<32)
+	// DISP_E_EXCEPTION represents an exception raised by an IDispatch member.
+	DISP_E_EXCEPTION = HRESULT(0x80020009 - 1<<32)
+	// DISP_E_BADPARAMCOUNT represents an IDispatch call with the wrong number of arguments.
+	DISP_E_BADPARAMCOUNT = HRESULT(0x8002000E - 1<<32)
+
+	// RPC_E_CALL_REJECTED represents a call rejected by the callee.
+	RPC_E_CALL_REJECTED = HRESULT(0x80010001 - 1<<32)
+	// RPC_E_SERVER_DIED represents a call whose server died before it completed.
+	RPC_E_SERVER_DIED = HRESULT(0x80010007 - 1<<32)
+	// RPC_E_SERVER_DIED_DNE represents a call whose server died before it was executed.
+	RPC_E_SERVER_DIED_DNE = HRESULT(0x80010012 - 1<<32)
+	// RPC_E_SERVERFAULT represents a server that raised an exception while processing a call.
+	RPC_E_SERVERFAULT = HRESULT(0x80010105 - 1<<32)
+	// RPC_E_CHANGED_MODE represents a change of the apartment model of a thread.
+	RPC_E_CHANGED_MODE = HRESULT(0x80010106 - 1<<32)
+	// RPC_E_DISCONNECTED represents an object that disconnected from its clients.
+	RPC_E_DISCONNECTED = HRESULT(0x80010108 - 1<<32)
+	// RPC_E_SERVERCALL_RETRYLATER represents a server that is too busy to process a call.
+	RPC_E_SERVERCALL_RETRYLATER = HRESULT(0x8001010A - 1<<32)
+	// RPC_E_WRONG_THREAD represents a call made on an interface marshalled for another thread.
+	RPC_E_WRONG_THREAD = HRESULT(0x8001010E - 1<<32)
+	// RPC_E_TIMEOUT represents a call that timed out.
+	RPC_E_TIMEOUT = HRESULT(0x8001011F - 1<<32)
+
+	// RPC_S_SERVER_UNAVAILABLE represents an unavailable RPC server (as an HRESULT).
+	RPC_S_SERVER_UNAVAILABLE = HRESULT(0x800706BA - 1<<32)
+	// RPC_S_CALL_FAILED represents a failed remote procedure call (as an HRESULT).
+	RPC_S_CALL_FAILED = HRESULT(0x800706BE - 1<<32)
+	// RPC_S_CALL_FAILED_DNE represents a remote procedure call that failed and did not execute (as an HRESULT).
+	RPC_S_CALL_FAILED_DNE = HRESULT(0x800706BF - 1<<32)
+)
+
+type wellKnownHRESULT struct {
+	name        string
+	description string
+}
+
+var wellKnown = map[HRESULT]wellKnownHRESULT{
+	S_OK:                        {"S_OK", "success"},
+	S_FALSE:                     {"S_FALSE", "success (false)"},
+	E_NOTIMPL:                   {"E_NOTIMPL", "not implemented"},
+	E_NOINTERFACE:               {"E_NOINTERFACE", "no such interface supported"},
+	E_POINTER:                   {"E_POINTER", "invalid pointer"},
+	E_ABORT:                     {"E_ABORT", "operation aborted"},
+	E_FAIL:                      {"E_FAIL", "unspecified error"},
+	E_UNEXPECTED:                {"E_UNEXPECTED", "catastrophic failure"},
+	E_ACCESSDENIED:              {"E_ACCESSDENIED", "general access denied error"},
+	E_HANDLE:                    {"E_HANDLE", "invalid handle"},
+	E_OUTOFMEMORY:               {"E_OUTOFMEMORY", "out of memory"},
+	E_INVALIDARG:                {"E_INVALIDARG", "one or more arguments are invalid"},
+	E_NOT_FOUND:                 {"E_NOT_FOUND", "element not found"},
+	E_ELEVATION_REQUIRED:        {"E_ELEVATION_REQUIRED", "the requested operation requires elevation"},
+	CLASS_E_NOAGGREGATION:       {"CLASS_E_NOAGGREGATION", "class does not support aggregation"},
+	REGDB_E_CLASSNOTREG:         {"REGDB_E_CLASSNOTREG", "class not registered"},
+	CO_E_NOTINITIALIZED:         {"CO_E_NOTINITIALIZED", "CoInitialize has not been called"},
+	CO_E_ALREADYINITIALIZED:     {"CO_E_ALREADYINITIALIZED", "CoInitialize has already been called"},
+	DISP_E_MEMBERNOTFOUND:       {"DISP_E_MEMBERNOTFOUND", "member not found"},
+	DISP_E_TYPEMISMATCH:         {"DISP_E_TYPEMISMATCH", "type mismatch"},
+	DISP_E_UNKNOWNNAME:          {"DISP_E_UNKNOWNNAME", "unknown name"},
+	DISP_E_EXCEPTION:            {"DISP_E_EXCEPTION", "exception occurred"},
+	DISP_E_BADPARAMCOUNT:        {"DISP_E_BADPARAMCOUNT", "invalid number of parameters"},
+	RPC_E_CALL_REJECTED:         {"RPC_E_CALL_REJECTED", "call was rejected by callee"},
+	RPC_E_SERVER_DIED:           {"RPC_E_SERVER_DIED", "the remote server died before the call completed"},
+	RPC_E_SERVER_DIED_DNE:       {"RPC_E_SERVER_DIED_DNE", "the remote server died before the call was executed"},
+	RPC_E_SERVERFAULT:           {"RPC_E_SERVERFAULT", "the server threw an exception"},
+	RPC_E_CHANGED_MODE:          {"RPC_E_CHANGED_MODE", "cannot change thread mode after it is set"},
+	RPC_E_DISCONNECTED:          {"RPC_E_DISCONNECTED", "the object invoked has disconnected from its clients"},
+	RPC_E_SERVERCALL_RETRYLATER: {"RPC_E_SERVERCALL_RETRYLATER", "the message filter indicated that the application is busy"},
+	RPC_E_WRONG_THREAD:          {"RPC_E_WRONG_THREAD", "the application called an interface that was marshalled for a different thread"},
+	RPC_E_TIMEOUT:               {"RPC_E_TIMEOUT", "this operation returned because the timeout period expired"},
+	RPC_S_SERVER_UNAVAILABLE:    {"RPC_S_SERVER_UNAVAILABLE", "the RPC server is unavailable"},
+	RPC_S_CALL_FAILED:           {"RPC_S_CALL_FAILED", "the remote procedure call failed"},
+	RPC_S_CALL_FAILED_DNE:       {"RPC_S_CALL_FAILED_DNE", "the remote procedure call failed and did not execute"},
+}
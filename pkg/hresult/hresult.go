@@ -0,0 +1,211 @@
+package hresult
+
+import (
+	"errors"
+	"fmt"
+)
+
+// HRESULT represents a Windows HRESULT, a 32-bit value made up of a severity
+// bit, a facility and a code. Failure HRESULTs implement the error interface
+// and, since HRESULT is comparable, can be matched with errors.Is against the
+// well-known values defined in this package e.g.
+//
+//	if errors.Is(err, hresult.E_ACCESSDENIED) {
+//		// handle access denied
+//	}
+//
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-erref/0642cb2f-2075-4469-918c-4441e69c548a
+type HRESULT int32
+
+// Facility represents the facility (source) of an HRESULT.
+type Facility uint16
+
+const (
+	// FacilityNull represents the default facility.
+	FacilityNull = Facility(0)
+	// FacilityRPC represents the RPC subsystem facility.
+	FacilityRPC = Facility(1)
+	// FacilityDispatch represents the COM IDispatch facility.
+	FacilityDispatch = Facility(2)
+	// FacilityStorage represents the OLE storage facility.
+	FacilityStorage = Facility(3)
+	// FacilityITF represents the facility of interface-specific errors.
+	FacilityITF = Facility(4)
+	// FacilityWin32 represents the facility of Win32 error codes.
+	FacilityWin32 = Facility(7)
+	// FacilityWindows represents the Windows subsystem facility.
+	FacilityWindows = Facility(8)
+)
+
+var facilityToString = map[Facility]string{
+	FacilityNull:     "NULL",
+	FacilityRPC:      "RPC",
+	FacilityDispatch: "DISPATCH",
+	FacilityStorage:  "STORAGE",
+	FacilityITF:      "ITF",
+	FacilityWin32:    "WIN32",
+	FacilityWindows:  "WINDOWS",
+}
+
+// String returns the string representation of the Facility.
+func (f Facility) String() string {
+	if str, ok := facilityToString[f]; ok {
+		return str
+	}
+	return fmt.Sprintf("%d", uint16(f))
+}
+
+// Make returns the HRESULT for the given severity (true for failure), facility and code.
+func Make(failure bool, facility Facility, code uint16) HRESULT {
+	hr := uint32(facility&0x7FF)<<16 | uint32(code)
+	if failure {
+		hr |= 0x80000000
+	}
+	return HRESULT(int32(hr))
+}
+
+// FromWin32 returns the HRESULT for a Win32 error code, like the HRESULT_FROM_WIN32 macro.
+func FromWin32(code uint32) HRESULT {
+	if int32(code) <= 0 {
+		return HRESULT(int32(code))
+	}
+	return Make(true, FacilityWin32, uint16(code))
+}
+
+// FromUintptr returns the HRESULT for the (first) return value of a COM method
+// call made with syscall.SyscallN, which only holds an HRESULT in its low 32 bits.
+func FromUintptr(r uintptr) HRESULT {
+	return HRESULT(int32(uint32(r)))
+}
+
+// Failed returns true if the HRESULT represents a failure (its severity bit is set).
+func (hr HRESULT) Failed() bool {
+	return hr < 0
+}
+
+// Succeeded returns true if the HRESULT represents a success (its severity bit is not set).
+func (hr HRESULT) Succeeded() bool {
+	return hr >= 0
+}
+
+// Severity returns the severity bit of the HRESULT (1 for failure, 0 for success).
+func (hr HRESULT) Severity() int {
+	return int(uint32(hr) >> 31)
+}
+
+// Facility returns the facility of the HRESULT.
+func (hr HRESULT) Facility() Facility {
+	return Facility((uint32(hr) >> 16) & 0x7FF)
+}
+
+// Code returns the code of the HRESULT.
+func (hr HRESULT) Code() uint16 {
+	return uint16(uint32(hr))
+}
+
+// Name returns the symbolic name of the HRESULT e.g. "E_ACCESSDENIED",
+// or an empty string if the HRESULT is not well-known by this package.
+func (hr HRESULT) Name() string {
+	return wellKnown[hr].name
+}
+
+// String returns the string representation of the HRESULT.
+func (hr HRESULT) String() string {
+	if known, ok := wellKnown[hr]; ok {
+		return fmt.Sprintf("%s (0x%08X): %s", known.name, uint32(hr), known.description)
+	}
+	return fmt.Sprintf("HRESULT 0x%08X (facility %s, code 0x%04X)", uint32(hr), hr.Facility(), hr.Code())
+}
+
+// Error implements the error interface.
+func (hr HRESULT) Error() string {
+	return hr.String()
+}
+
+// Error is an error carrying an HRESULT along with the underlying
+// error (e.g. an *ole.OleError) that the HRESULT was decoded from.
+type Error struct {
+	HRESULT HRESULT
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err == nil || e.Err.Error() == "" {
+		return e.HRESULT.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.HRESULT.Error(), e.Err)
+}
+
+// Unwrap returns both the HRESULT and the underlying error, such that
+// errors.Is and errors.As can match against either of them.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.HRESULT}
+	}
+	return []error{e.HRESULT, e.Err}
+}
+
+// FromError returns the HRESULT carried by an error, if any. Besides HRESULTs
+// (and errors wrapping them) this understands errors exposing a Code() uintptr
+// method, such as *ole.OleError. For DISP_E_EXCEPTION errors raised by
+// IDispatch invocations the HRESULT of the exception itself is returned when
+// available.
+func FromError(err error) (HRESULT, bool) {
+	var hr HRESULT
+	if errors.As(err, &hr) {
+		return hr, true
+	}
+	var coded interface{ Code() uintptr }
+	if !errors.As(err, &coded) {
+		return 0, false
+	}
+	hr = FromUintptr(coded.Code())
+	if hr == DISP_E_EXCEPTION {
+		if scode := exceptionSCODE(err); scode != 0 {
+			hr = HRESULT(int32(scode))
+		}
+	}
+	return hr, true
+}
+
+// exceptionSCODE returns the SCODE of the EXCEPINFO carried by an error, or 0 if
+// none. *ole.OleError does not implement Unwrap, and only exposes the EXCEPINFO
+// of DISP_E_EXCEPTION errors through its SubError method.
+func exceptionSCODE(err error) uint32 {
+	var excepInfo interface{ SCODE() uint32 }
+	if errors.As(err, &excepInfo) {
+		return excepInfo.SCODE()
+	}
+	var parent interface{ SubError() error }
+	if errors.As(err, &parent) && parent.SubError() != nil {
+		return exceptionSCODE(parent.SubError())
+	}
+	return 0
+}
+
+// Wrap returns an error whose chain includes the HRESULT carried by err, such
+// that it can be matched with errors.Is. Errors which already include an
+// HRESULT in their chain, or which do not carry one, are returned as is.
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	var hr HRESULT
+	if errors.As(err, &hr) {
+		return err
+	}
+	if hr, ok := FromError(err); ok {
+		return &Error{HRESULT: hr, Err: err}
+	}
+	return err
+}
+
+// Check returns nil for successful HRESULTs, and the HRESULT
+// itself (as an error) for failed ones.
+func Check(hr HRESULT) error {
+	if hr.Failed() {
+		return hr
+	}
+	return nil
+}
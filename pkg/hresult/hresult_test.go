@@ -0,0 +1,166 @@
+package hresult
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-ole/go-ole"
+)
+
+// excepInfo is an error exposing an SCODE like ole.EXCEPINFO, whose
+// fields can only be set by IDispatch::Invoke.
+type excepInfo struct{ scode uint32 }
+
+func (e excepInfo) Error() string { return "exception" }
+func (e excepInfo) SCODE() uint32 { return e.scode }
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		failure  bool
+		facility Facility
+		code     uint16
+		want     HRESULT
+	}{
+		{false, FacilityNull, 0, S_OK},
+		{false, FacilityNull, 1, S_FALSE},
+		{true, FacilityNull, 0x4005, E_FAIL},
+		{true, FacilityWin32, 5, E_ACCESSDENIED},
+		{true, FacilityWin32, 0x490, E_NOT_FOUND},
+		{true, FacilityDispatch, 9, DISP_E_EXCEPTION},
+		{true, FacilityRPC, 7, RPC_E_SERVER_DIED},
+	}
+	for _, test := range tests {
+		hr := Make(test.failure, test.facility, test.code)
+		if hr != test.want {
+			t.Errorf("Make(%t, %s, 0x%X) = 0x%08X, want 0x%08X", test.failure, test.facility, test.code, uint32(hr), uint32(test.want))
+		}
+		if hr.Failed() != test.failure || hr.Succeeded() == test.failure {
+			t.Errorf("0x%08X Failed() = %t, Succeeded() = %t", uint32(hr), hr.Failed(), hr.Succeeded())
+		}
+		if want := map[bool]int{false: 0, true: 1}[test.failure]; hr.Severity() != want {
+			t.Errorf("0x%08X Severity() = %d, want %d", uint32(hr), hr.Severity(), want)
+		}
+		if hr.Facility() != test.facility {
+			t.Errorf("0x%08X Facility() = %s, want %s", uint32(hr), hr.Facility(), test.facility)
+		}
+		if hr.Code() != test.code {
+			t.Errorf("0x%08X Code() = 0x%X, want 0x%X", uint32(hr), hr.Code(), test.code)
+		}
+	}
+}
+
+func TestFromWin32(t *testing.T) {
+	tests := []struct {
+		code uint32
+		want HRESULT
+	}{
+		{0, S_OK},
+		{5, E_ACCESSDENIED},
+		{0x490, E_NOT_FOUND},
+		{0x80004005, E_FAIL},
+	}
+	for _, test := range tests {
+		if got := FromWin32(test.code); got != test.want {
+			t.Errorf("FromWin32(0x%X) = 0x%08X, want 0x%08X", test.code, uint32(got), uint32(test.want))
+		}
+	}
+	if got := FromUintptr(uintptr(0x80070005)); got != E_ACCESSDENIED {
+		t.Errorf("FromUintptr = 0x%08X, want E_ACCESSDENIED", uint32(got))
+	}
+}
+
+func TestString(t *testing.T) {
+	if got, want := E_ACCESSDENIED.Error(), "E_ACCESSDENIED (0x80070005): general access denied error"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := Make(true, FacilityITF, 0x200).String(), "HRESULT 0x80040200 (facility ITF, code 0x0200)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := Make(true, Facility(0x123), 1).String(), "HRESULT 0x81230001 (facility 291, code 0x0001)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if E_NOT_FOUND.Name() != "E_NOT_FOUND" || Make(true, FacilityITF, 0x200).Name() != "" {
+		t.Errorf("Name() = %q, %q", E_NOT_FOUND.Name(), Make(true, FacilityITF, 0x200).Name())
+	}
+}
+
+func TestFromError(t *testing.T) {
+	dispException := uintptr(0x80020009)
+	tests := []struct {
+		name string
+		err  error
+		want HRESULT
+		ok   bool
+	}{
+		{"nil", nil, 0, false},
+		{"other", errors.New("other"), 0, false},
+		{"HRESULT", E_ACCESSDENIED, E_ACCESSDENIED, true},
+		{"wrapped HRESULT", fmt.Errorf("failed: %w", E_NOT_FOUND), E_NOT_FOUND, true},
+		{"OleError", ole.NewError(uintptr(0x80070005)), E_ACCESSDENIED, true},
+		{"wrapped OleError", fmt.Errorf("failed: %w", ole.NewError(uintptr(0x80070490))), E_NOT_FOUND, true},
+		{"exception", ole.NewErrorWithSubError(dispException, "denied", excepInfo{scode: 0x80070005}), E_ACCESSDENIED, true},
+		{"wrapped exception", fmt.Errorf("failed: %w", ole.NewErrorWithSubError(dispException, "", excepInfo{scode: 0x80070490})), E_NOT_FOUND, true},
+		{"exception without SCODE", ole.NewErrorWithSubError(dispException, "", ole.EXCEPINFO{}), DISP_E_EXCEPTION, true},
+		{"exception without EXCEPINFO", ole.NewError(dispException), DISP_E_EXCEPTION, true},
+		{"SCODE of other errors", ole.NewErrorWithSubError(uintptr(0x80004005), "", excepInfo{scode: 0x80070005}), E_FAIL, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hr, ok := FromError(test.err)
+			if hr != test.want || ok != test.ok {
+				t.Errorf("FromError = 0x%08X, %t, want 0x%08X, %t", uint32(hr), ok, uint32(test.want), test.ok)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(nil) != nil {
+		t.Errorf("Wrap(nil) != nil")
+	}
+	other := errors.New("other")
+	if Wrap(other) != other {
+		t.Errorf("Wrap changed an error without an HRESULT")
+	}
+	wrapped := fmt.Errorf("failed: %w", E_FAIL)
+	if Wrap(wrapped) != wrapped {
+		t.Errorf("Wrap changed an error with an HRESULT")
+	}
+
+	oleErr := ole.NewErrorWithSubError(uintptr(0x80020009), "denied", excepInfo{scode: 0x80070005})
+	err := Wrap(oleErr)
+	if !errors.Is(err, E_ACCESSDENIED) {
+		t.Errorf("errors.Is(%v, E_ACCESSDENIED) = false", err)
+	}
+	if errors.Is(err, DISP_E_EXCEPTION) {
+		t.Errorf("errors.Is(%v, DISP_E_EXCEPTION) = true", err)
+	}
+	var target *ole.OleError
+	if !errors.As(err, &target) || target != oleErr {
+		t.Errorf("errors.As(%v, *ole.OleError) did not find the original error", err)
+	}
+	var hrErr *Error
+	if !errors.As(err, &hrErr) || hrErr.HRESULT != E_ACCESSDENIED {
+		t.Errorf("errors.As(%v, *Error) = %+v", err, hrErr)
+	}
+	if got, want := err.Error(), E_ACCESSDENIED.Error()+": "+oleErr.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestErrorsIs(t *testing.T) {
+	err := fmt.Errorf("failed to get network: %w", &Error{HRESULT: E_NOT_FOUND})
+	if !errors.Is(err, E_NOT_FOUND) {
+		t.Errorf("errors.Is(E_NOT_FOUND) = false")
+	}
+	if errors.Is(err, E_ACCESSDENIED) {
+		t.Errorf("errors.Is(E_ACCESSDENIED) = true")
+	}
+	if got := (&Error{HRESULT: E_NOT_FOUND}).Error(); got != E_NOT_FOUND.Error() {
+		t.Errorf("Error() = %q, want %q", got, E_NOT_FOUND.Error())
+	}
+	if Check(S_FALSE) != nil || !errors.Is(Check(E_FAIL), E_FAIL) {
+		t.Errorf("Check(S_FALSE) = %v, Check(E_FAIL) = %v", Check(S_FALSE), Check(E_FAIL))
+	}
+}
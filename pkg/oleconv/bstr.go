@@ -0,0 +1,53 @@
+package oleconv
+
+import (
+	"unicode/utf16"
+	"unsafe"
+)
+
+// BSTRLen returns the length, in UTF-16 code units, of a BSTR (a pointer to
+// the first character of a string which is prefixed by its length in bytes).
+// A nil BSTR is a valid empty string.
+//
+// https://learn.microsoft.com/en-us/previous-versions/windows/desktop/automat/bstr
+func BSTRLen(bstr *uint16) int {
+	if bstr == nil {
+		return 0
+	}
+	prefix := (*uint32)(unsafe.Add(unsafe.Pointer(bstr), -4))
+	return int(*prefix / 2)
+}
+
+// BSTRToString returns the Go string for a BSTR. Unlike converting the BSTR as
+// a NUL-terminated string, this honours the BSTR's length prefix and therefore
+// preserves any embedded NUL characters.
+func BSTRToString(bstr *uint16) string {
+	n := BSTRLen(bstr)
+	if n == 0 {
+		return ""
+	}
+	return string(utf16.Decode(unsafe.Slice(bstr, n)))
+}
+
+// EncodeBSTR returns the in-memory layout of a BSTR holding s: a 4-byte length
+// prefix followed by the UTF-16 characters and a terminating NUL. The BSTR
+// itself is the address of the third element, see BSTRFromBuffer.
+//
+// BSTRs passed to COM methods must be allocated with SysAllocString, so this
+// is meant for BSTRs that never leave Go e.g. in fakes and tests.
+func EncodeBSTR(s string) []uint16 {
+	chars := utf16.Encode([]rune(s))
+	size := uint32(len(chars) * 2)
+	buf := make([]uint16, 0, len(chars)+3)
+	buf = append(buf, uint16(size), uint16(size>>16))
+	buf = append(buf, chars...)
+	return append(buf, 0)
+}
+
+// BSTRFromBuffer returns the BSTR for a buffer returned by EncodeBSTR.
+func BSTRFromBuffer(buf []uint16) *uint16 {
+	if len(buf) < 3 {
+		return nil
+	}
+	return &buf[2]
+}
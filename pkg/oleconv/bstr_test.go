@@ -0,0 +1,62 @@
+package oleconv
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBSTR(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"Network",
+		"Réseau 2",
+		"emoji 🛜",
+		"embedded\x00NUL",
+	} {
+		buf := EncodeBSTR(s)
+		bstr := BSTRFromBuffer(buf)
+		if got := BSTRToString(bstr); got != s {
+			t.Errorf("BSTRToString(EncodeBSTR(%q)) = %q", s, got)
+		}
+		if buf[len(buf)-1] != 0 {
+			t.Errorf("EncodeBSTR(%q) is not NUL-terminated", s)
+		}
+		if want := len(buf) - 3; BSTRLen(bstr) != want {
+			t.Errorf("BSTRLen(EncodeBSTR(%q)) = %d, want %d", s, BSTRLen(bstr), want)
+		}
+	}
+}
+
+func TestEncodeBSTR(t *testing.T) {
+	want := []uint16{4, 0, 'h', 'i', 0}
+	if got := EncodeBSTR("hi"); !slices.Equal(got, want) {
+		t.Errorf("EncodeBSTR(\"hi\") = %v, want %v", got, want)
+	}
+	// a surrogate pair is two UTF-16 code units
+	if got := BSTRLen(BSTRFromBuffer(EncodeBSTR("🛜"))); got != 2 {
+		t.Errorf("BSTRLen = %d, want 2", got)
+	}
+	// the length prefix is 32 bits
+	long := make([]rune, 40000)
+	for i := range long {
+		long[i] = 'x'
+	}
+	buf := EncodeBSTR(string(long))
+	if buf[0] != uint16(80000&0xFFFF) || buf[1] != 1 {
+		t.Errorf("length prefix = %#x %#x, want 80000 bytes", buf[0], buf[1])
+	}
+	if got := BSTRLen(BSTRFromBuffer(buf)); got != len(long) {
+		t.Errorf("BSTRLen = %d, want %d", got, len(long))
+	}
+}
+
+func TestBSTRNil(t *testing.T) {
+	if BSTRLen(nil) != 0 || BSTRToString(nil) != "" {
+		t.Errorf("nil BSTR is not the empty string")
+	}
+	for _, buf := range [][]uint16{nil, {0, 0}} {
+		if BSTRFromBuffer(buf) != nil {
+			t.Errorf("BSTRFromBuffer(%v) != nil", buf)
+		}
+	}
+}
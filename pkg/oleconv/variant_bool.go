@@ -0,0 +1,36 @@
+package oleconv
+
+// VariantBool represents the Windows VARIANT_BOOL type, a 16-bit
+// integer which is all ones (-1) for true and zero for false.
+//
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-oaut/7b39eb24-9d39-498a-bcd8-75c38e5823d0
+type VariantBool int16
+
+const (
+	// VariantTrue represents the VARIANT_TRUE value.
+	VariantTrue = VariantBool(-1)
+	// VariantFalse represents the VARIANT_FALSE value.
+	VariantFalse = VariantBool(0)
+)
+
+// VariantBoolFromBool returns the VariantBool for a bool.
+func VariantBoolFromBool(b bool) VariantBool {
+	if b {
+		return VariantTrue
+	}
+	return VariantFalse
+}
+
+// Bool returns the VariantBool as a bool. Any non-zero value is considered
+// true, since not all COM servers use VARIANT_TRUE consistently.
+func (b VariantBool) Bool() bool {
+	return b != VariantFalse
+}
+
+// String returns the string representation of the VariantBool.
+func (b VariantBool) String() string {
+	if b.Bool() {
+		return "VARIANT_TRUE"
+	}
+	return "VARIANT_FALSE"
+}
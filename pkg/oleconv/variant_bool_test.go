@@ -0,0 +1,30 @@
+package oleconv
+
+import "testing"
+
+func TestVariantBool(t *testing.T) {
+	tests := []struct {
+		value VariantBool
+		want  bool
+		str   string
+	}{
+		{VariantTrue, true, "VARIANT_TRUE"},
+		{VariantFalse, false, "VARIANT_FALSE"},
+		{VariantBool(1), true, "VARIANT_TRUE"},
+		{VariantBool(-2), true, "VARIANT_TRUE"},
+	}
+	for _, test := range tests {
+		if got := test.value.Bool(); got != test.want {
+			t.Errorf("VariantBool(%d).Bool() = %t, want %t", int16(test.value), got, test.want)
+		}
+		if got := test.value.String(); got != test.str {
+			t.Errorf("VariantBool(%d).String() = %q, want %q", int16(test.value), got, test.str)
+		}
+	}
+	if VariantBoolFromBool(true) != VariantTrue || VariantBoolFromBool(false) != VariantFalse {
+		t.Errorf("VariantBoolFromBool = %d, %d", VariantBoolFromBool(true), VariantBoolFromBool(false))
+	}
+	if v := VariantTrue; uint16(v) != 0xFFFF {
+		t.Errorf("VariantTrue = %#x, want 0xFFFF", uint16(v))
+	}
+}
@@ -0,0 +1,129 @@
+package wintime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// number of 100-nanosecond intervals in a second
+	intervalsPerSecond = int64(10000000)
+	// seconds between January 1, 1601 and January 1, 1970
+	secondsBetween1601And1970 = intervalsBetween1601And1970 / intervalsPerSecond
+	// the largest FILETIME that represents an actual point in time, as
+	// values with the most significant bit set are not valid FILETIMEs
+	maxValidIntervals = uint64(math.MaxInt64 - 1)
+)
+
+var (
+	// ErrFILETIMEOutOfRange is returned when a time or duration
+	// cannot be represented as a valid FILETIME.
+	ErrFILETIMEOutOfRange = errors.New("time out of FILETIME range")
+)
+
+// FILETIME represents the Windows FILETIME structure, a 64-bit value split
+// into two 32-bit halves holding the number of 100-nanosecond intervals since
+// January 1, 1601 (UTC).
+//
+// The zero FILETIME as well as the maximum values (0x7FFFFFFFFFFFFFFF and
+// 0xFFFFFFFFFFFFFFFF) are commonly used by Windows to mean "unset" or "never",
+// and are treated as such by this package.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/minwinbase/ns-minwinbase-filetime
+type FILETIME struct {
+	LowDateTime  uint32
+	HighDateTime uint32
+}
+
+// FILETIMEFromIntervals returns the FILETIME for a full 64-bit number of
+// 100-nanosecond intervals since January 1, 1601 (UTC).
+func FILETIMEFromIntervals(intervals uint64) FILETIME {
+	return FILETIME{LowDateTime: uint32(intervals), HighDateTime: uint32(intervals >> 32)}
+}
+
+// FromTime returns the FILETIME for a time.Time, truncated to 100-nanosecond
+// precision. The zero time.Time results in the zero (unset) FILETIME, and
+// times outside of the range of valid FILETIMEs result in an error.
+func FromTime(t time.Time) (FILETIME, error) {
+	if t.IsZero() {
+		return FILETIME{}, nil
+	}
+	// work with seconds and nanoseconds separately since the
+	// full range of FILETIMEs does not fit in int64 nanoseconds
+	secs := t.Unix()
+	if secs < -secondsBetween1601And1970 {
+		return FILETIME{}, fmt.Errorf("%w: %s is before January 1, 1601", ErrFILETIMEOutOfRange, t.UTC())
+	}
+	secsSince1601 := uint64(secs + secondsBetween1601And1970)
+	if secsSince1601 > maxValidIntervals/uint64(intervalsPerSecond) {
+		return FILETIME{}, fmt.Errorf("%w: %s is too far in the future", ErrFILETIMEOutOfRange, t.UTC())
+	}
+	intervals := secsSince1601*uint64(intervalsPerSecond) + uint64(t.Nanosecond()/100)
+	if intervals > maxValidIntervals {
+		return FILETIME{}, fmt.Errorf("%w: %s is too far in the future", ErrFILETIMEOutOfRange, t.UTC())
+	}
+	return FILETIMEFromIntervals(intervals), nil
+}
+
+// Intervals returns the full 64-bit number of 100-nanosecond
+// intervals since January 1, 1601 (UTC) of the FILETIME.
+func (ft FILETIME) Intervals() uint64 {
+	return uint64(ft.HighDateTime)<<32 | uint64(ft.LowDateTime)
+}
+
+// IsUnset returns true if the FILETIME is the zero value or one
+// of the maximum values used by Windows to mean "never".
+func (ft FILETIME) IsUnset() bool {
+	intervals := ft.Intervals()
+	return intervals == 0 || intervals > maxValidIntervals
+}
+
+// Time returns the FILETIME as a time.Time in UTC. Unset FILETIMEs
+// result in the zero time.Time and a false boolean.
+func (ft FILETIME) Time() (time.Time, bool) {
+	if ft.IsUnset() {
+		return time.Time{}, false
+	}
+	intervals := ft.Intervals()
+	secs := int64(intervals/uint64(intervalsPerSecond)) - secondsBetween1601And1970
+	nsecs := int64(intervals%uint64(intervalsPerSecond)) * 100
+	return time.Unix(secs, nsecs).UTC(), true
+}
+
+// Add returns the FILETIME ft+d. An error is returned if the
+// result would fall outside of the range of valid FILETIMEs.
+func (ft FILETIME) Add(d time.Duration) (FILETIME, error) {
+	intervals := ft.Intervals()
+	delta := d / 100
+	if delta >= 0 {
+		if uint64(delta) > maxValidIntervals-min(intervals, maxValidIntervals) {
+			return FILETIME{}, fmt.Errorf("%w: adding %s overflows", ErrFILETIMEOutOfRange, d)
+		}
+		return FILETIMEFromIntervals(intervals + uint64(delta)), nil
+	}
+	// -delta cannot overflow since delta is at most math.MinInt64/100
+	if uint64(-delta) > intervals {
+		return FILETIME{}, fmt.Errorf("%w: subtracting %s underflows", ErrFILETIMEOutOfRange, -d)
+	}
+	return FILETIMEFromIntervals(intervals - uint64(-delta)), nil
+}
+
+// Sub returns the duration ft-u. If the result exceeds the maximum (or minimum)
+// value that can be stored in a time.Duration, the maximum (or minimum)
+// duration will be returned.
+func (ft FILETIME) Sub(u FILETIME) time.Duration {
+	a, b := ft.Intervals(), u.Intervals()
+	const maxIntervals = uint64(math.MaxInt64 / 100)
+	if a >= b {
+		if a-b > maxIntervals {
+			return time.Duration(math.MaxInt64)
+		}
+		return time.Duration(a-b) * 100
+	}
+	if b-a > maxIntervals {
+		return time.Duration(math.MinInt64)
+	}
+	return -time.Duration(b-a) * 100
+}
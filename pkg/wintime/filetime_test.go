@@ -0,0 +1,147 @@
+package wintime
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFILETIMETime(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals uint64
+		want      time.Time
+	}{
+		{"epoch", 1, time.Date(1601, 1, 1, 0, 0, 0, 100, time.UTC)},
+		{"unix epoch", uint64(intervalsBetween1601And1970), time.Unix(0, 0).UTC()},
+		{"date", uint64(133486382450000000), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"sub-second", uint64(133486382451234567), time.Date(2024, 1, 2, 3, 4, 5, 123456700, time.UTC)},
+		{"last valid", maxValidIntervals, time.Unix(int64(maxValidIntervals/10000000)-secondsBetween1601And1970, 477580600).UTC()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := FILETIMEFromIntervals(test.intervals).Time()
+			if !ok {
+				t.Fatalf("Time() is unset")
+			}
+			if !got.Equal(test.want) || got.Location() != time.UTC {
+				t.Errorf("Time() = %s, want %s", got, test.want)
+			}
+			ft, err := FromTime(test.want)
+			if err != nil {
+				t.Fatalf("FromTime(%s) failed: %v", test.want, err)
+			}
+			if ft.Intervals() != test.intervals {
+				t.Errorf("FromTime(%s) = %d, want %d", test.want, ft.Intervals(), test.intervals)
+			}
+		})
+	}
+}
+
+func TestFILETIMEHalves(t *testing.T) {
+	ft := FILETIMEFromIntervals(0x0123456789ABCDEF)
+	if ft.LowDateTime != 0x89ABCDEF || ft.HighDateTime != 0x01234567 {
+		t.Errorf("FILETIMEFromIntervals = %#x/%#x", ft.HighDateTime, ft.LowDateTime)
+	}
+	if ft.Intervals() != 0x0123456789ABCDEF {
+		t.Errorf("Intervals() = %#x", ft.Intervals())
+	}
+}
+
+func TestFILETIMEUnset(t *testing.T) {
+	for _, ft := range []FILETIME{
+		{},
+		FILETIMEFromIntervals(math.MaxInt64),
+		FILETIMEFromIntervals(math.MaxUint64),
+		{LowDateTime: 0xFFFFFFFF, HighDateTime: 0x80000000},
+	} {
+		if !ft.IsUnset() {
+			t.Errorf("%#x IsUnset() = false", ft.Intervals())
+		}
+		if got, ok := ft.Time(); ok || !got.IsZero() {
+			t.Errorf("%#x Time() = %s, %t, want zero time", ft.Intervals(), got, ok)
+		}
+	}
+	if ft, err := FromTime(time.Time{}); err != nil || ft != (FILETIME{}) {
+		t.Errorf("FromTime(zero) = %+v, %v, want zero FILETIME", ft, err)
+	}
+}
+
+func TestFromTimePreservesInstant(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	local := time.Date(2024, 1, 1, 22, 4, 5, 123456789, loc)
+	ft, err := FromTime(local)
+	if err != nil {
+		t.Fatalf("FromTime failed: %v", err)
+	}
+	got, _ := ft.Time()
+	want := local.Truncate(100 * time.Nanosecond)
+	if !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("round trip = %s, want %s in UTC", got, want.UTC())
+	}
+}
+
+func TestFromTimeOutOfRange(t *testing.T) {
+	for _, tm := range []time.Time{
+		time.Date(1600, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(30829, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if _, err := FromTime(tm); !errors.Is(err, ErrFILETIMEOutOfRange) {
+			t.Errorf("FromTime(%s) error = %v, want ErrFILETIMEOutOfRange", tm, err)
+		}
+	}
+	if _, err := FromTime(time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("FromTime(1601-01-01) failed: %v", err)
+	}
+}
+
+func TestFILETIMEAdd(t *testing.T) {
+	ft := FILETIMEFromIntervals(1000)
+	if got, err := ft.Add(time.Microsecond); err != nil || got.Intervals() != 1010 {
+		t.Errorf("Add(1µs) = %d, %v, want 1010", got.Intervals(), err)
+	}
+	if got, err := ft.Add(-100 * time.Microsecond); err != nil || got.Intervals() != 0 {
+		t.Errorf("Add(-100µs) = %d, %v, want 0", got.Intervals(), err)
+	}
+	if _, err := ft.Add(-101 * time.Microsecond); !errors.Is(err, ErrFILETIMEOutOfRange) {
+		t.Errorf("Add(-101µs) error = %v, want ErrFILETIMEOutOfRange", err)
+	}
+	if _, err := FILETIMEFromIntervals(maxValidIntervals).Add(100); !errors.Is(err, ErrFILETIMEOutOfRange) {
+		t.Errorf("Add past the last valid FILETIME error = %v, want ErrFILETIMEOutOfRange", err)
+	}
+	if _, err := FILETIMEFromIntervals(math.MaxUint64).Add(time.Duration(math.MinInt64)); err != nil {
+		t.Errorf("Add(min duration) to the max FILETIME failed: %v", err)
+	}
+	if _, err := (FILETIME{}).Add(time.Duration(math.MinInt64)); !errors.Is(err, ErrFILETIMEOutOfRange) {
+		t.Errorf("Add(min duration) to the zero FILETIME error = %v, want ErrFILETIMEOutOfRange", err)
+	}
+}
+
+func TestFILETIMESub(t *testing.T) {
+	a, b := FILETIMEFromIntervals(1000), FILETIMEFromIntervals(10)
+	if got := a.Sub(b); got != 99*time.Microsecond {
+		t.Errorf("Sub = %s, want 99µs", got)
+	}
+	if got := b.Sub(a); got != -99*time.Microsecond {
+		t.Errorf("Sub = %s, want -99µs", got)
+	}
+	max, zero := FILETIMEFromIntervals(maxValidIntervals), FILETIME{}
+	if got := max.Sub(zero); got != time.Duration(math.MaxInt64) {
+		t.Errorf("Sub saturates to %d, want max duration", got)
+	}
+	if got := zero.Sub(max); got != time.Duration(math.MinInt64) {
+		t.Errorf("Sub saturates to %d, want min duration", got)
+	}
+}
+
+func TestToTime(t *testing.T) {
+	intervals := uint64(133486382450000000)
+	got := ToTime(int64(uint32(intervals)), int64(intervals>>32))
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !got.Equal(want) || got.Location() != time.Local {
+		t.Errorf("ToTime = %s, want %s in local time", got, want)
+	}
+	if got := ToTime(0, 0); !got.IsZero() {
+		t.Errorf("ToTime(0, 0) = %s, want zero time", got)
+	}
+}
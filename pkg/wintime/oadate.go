@@ -0,0 +1,64 @@
+package wintime
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// lower (exclusive) bound of valid OLE Automation dates
+	minOADate = OADate(-657435.0)
+	// largest valid OLE Automation date (December 31, 9999, 23:59:59.999)
+	maxOADate = OADate(2958465.99999999)
+	// number of milliseconds in a day
+	millisecondsPerDay = float64(24 * time.Hour / time.Millisecond)
+)
+
+// oaDateEpoch is the OLE Automation date epoch (midnight, December 30, 1899).
+var oaDateEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// OADate represents an OLE Automation date (the VT_DATE VARIANT type), a
+// floating-point number whose integral part is the number of days since
+// December 30, 1899 and whose fractional part is the time of day. For
+// negative dates the fractional part is still counted forward from
+// midnight, so -1.25 is 06:00 on December 29, 1899.
+//
+// Like SYSTEMTIME, an OADate carries no time zone.
+//
+// https://learn.microsoft.com/en-us/dotnet/api/system.datetime.tooadate
+type OADate float64
+
+// OADateFromTime returns the OADate for the wall clock of a time.Time
+// (in its own location), with millisecond precision.
+func OADateFromTime(t time.Time) (OADate, error) {
+	if t.Year() < 100 || t.Year() > 9999 {
+		return 0, fmt.Errorf("year %d out of OLE Automation date range [100, 9999]", t.Year())
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	wallClock := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+
+	// use unix seconds since the full range of dates overflows a time.Duration
+	days := float64((midnight.Unix() - oaDateEpoch.Unix()) / int64(24*time.Hour/time.Second))
+	fraction := float64(wallClock.Sub(midnight).Milliseconds()) / millisecondsPerDay
+	if days < 0 {
+		return OADate(days - fraction), nil
+	}
+	return OADate(days + fraction), nil
+}
+
+// Time returns the OADate as a time.Time in the given location (UTC if nil),
+// rounded to the nearest millisecond.
+func (d OADate) Time(loc *time.Location) (time.Time, error) {
+	if math.IsNaN(float64(d)) || d <= minOADate || d > maxOADate {
+		return time.Time{}, fmt.Errorf("invalid OLE Automation date %v", float64(d))
+	}
+	days, fraction := math.Modf(float64(d))
+	millis := math.Round(math.Abs(fraction) * millisecondsPerDay)
+
+	if loc == nil {
+		loc = time.UTC
+	}
+	t := oaDateEpoch.AddDate(0, 0, int(days)).Add(time.Duration(millis) * time.Millisecond)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), nil
+}
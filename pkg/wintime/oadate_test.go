@@ -0,0 +1,81 @@
+package wintime
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOADate(t *testing.T) {
+	tests := []struct {
+		date OADate
+		want time.Time
+	}{
+		{0, time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{1, time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{2.5, time.Date(1900, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{-1, time.Date(1899, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{-1.25, time.Date(1899, 12, 29, 6, 0, 0, 0, time.UTC)},
+		{-2.75, time.Date(1899, 12, 28, 18, 0, 0, 0, time.UTC)},
+		{45000.25, time.Date(2023, 3, 15, 6, 0, 0, 0, time.UTC)},
+		{-657434, time.Date(100, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{2958465, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		got, err := test.date.Time(time.UTC)
+		if err != nil {
+			t.Errorf("OADate(%v).Time failed: %v", float64(test.date), err)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("OADate(%v).Time = %s, want %s", float64(test.date), got, test.want)
+		}
+		date, err := OADateFromTime(test.want)
+		if err != nil {
+			t.Errorf("OADateFromTime(%s) failed: %v", test.want, err)
+			continue
+		}
+		if date != test.date {
+			t.Errorf("OADateFromTime(%s) = %v, want %v", test.want, float64(date), float64(test.date))
+		}
+	}
+}
+
+func TestOADateWallClock(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 678000000, loc)
+	date, err := OADateFromTime(tm)
+	if err != nil {
+		t.Fatalf("OADateFromTime failed: %v", err)
+	}
+	got, err := date.Time(loc)
+	if err != nil {
+		t.Fatalf("Time failed: %v", err)
+	}
+	if !got.Equal(tm) {
+		t.Errorf("round trip = %s, want %s", got, tm)
+	}
+	got, err = date.Time(nil)
+	if err != nil {
+		t.Fatalf("Time(nil) failed: %v", err)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC); !got.Equal(want) {
+		t.Errorf("Time(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestOADateInvalid(t *testing.T) {
+	for _, date := range []OADate{minOADate, maxOADate + 1, OADate(math.NaN()), OADate(math.Inf(1))} {
+		if got, err := date.Time(time.UTC); err == nil {
+			t.Errorf("OADate(%v).Time = %s, want error", float64(date), got)
+		}
+	}
+	for _, tm := range []time.Time{
+		time.Date(99, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if _, err := OADateFromTime(tm); err == nil {
+			t.Errorf("OADateFromTime(%s) succeeded", tm)
+		}
+	}
+}
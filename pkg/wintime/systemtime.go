@@ -0,0 +1,142 @@
+package wintime
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// size in bytes of the binary form of a SYSTEMTIME
+const systemTimeSize = 16
+
+// SYSTEMTIME represents the Windows SYSTEMTIME structure, a date and time
+// broken down into its calendar fields. A SYSTEMTIME carries no time zone,
+// so whether it holds UTC or local time depends on where it came from.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/minwinbase/ns-minwinbase-systemtime
+type SYSTEMTIME struct {
+	Year         uint16
+	Month        uint16
+	DayOfWeek    uint16
+	Day          uint16
+	Hour         uint16
+	Minute       uint16
+	Second       uint16
+	Milliseconds uint16
+}
+
+// SYSTEMTIMEFromTime returns the SYSTEMTIME for the wall clock of a
+// time.Time (in its own location), truncated to millisecond precision.
+// The zero time.Time results in the zero (unset) SYSTEMTIME.
+func SYSTEMTIMEFromTime(t time.Time) (SYSTEMTIME, error) {
+	if t.IsZero() {
+		return SYSTEMTIME{}, nil
+	}
+	if t.Year() < 1601 || t.Year() > 30827 {
+		return SYSTEMTIME{}, fmt.Errorf("year %d out of SYSTEMTIME range [1601, 30827]", t.Year())
+	}
+	return SYSTEMTIME{
+		Year:         uint16(t.Year()),
+		Month:        uint16(t.Month()),
+		DayOfWeek:    uint16(t.Weekday()),
+		Day:          uint16(t.Day()),
+		Hour:         uint16(t.Hour()),
+		Minute:       uint16(t.Minute()),
+		Second:       uint16(t.Second()),
+		Milliseconds: uint16(t.Nanosecond() / int(time.Millisecond)),
+	}, nil
+}
+
+// ParseSYSTEMTIME decodes a SYSTEMTIME from its 16-byte little-endian binary form
+// e.g. as stored in REG_BINARY registry values.
+func ParseSYSTEMTIME(b []byte) (SYSTEMTIME, error) {
+	var st SYSTEMTIME
+	if err := st.UnmarshalBinary(b); err != nil {
+		return SYSTEMTIME{}, err
+	}
+	return st, nil
+}
+
+// IsZero returns true if the SYSTEMTIME is the zero value.
+func (st SYSTEMTIME) IsZero() bool {
+	return st == SYSTEMTIME{}
+}
+
+// Time returns the SYSTEMTIME as a time.Time in the given location (UTC if nil).
+// The zero SYSTEMTIME results in the zero time.Time. The DayOfWeek field is ignored.
+func (st SYSTEMTIME) Time(loc *time.Location) (time.Time, error) {
+	if st.IsZero() {
+		return time.Time{}, nil
+	}
+	if err := st.validate(); err != nil {
+		return time.Time{}, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return time.Date(
+		int(st.Year),
+		time.Month(st.Month),
+		int(st.Day),
+		int(st.Hour),
+		int(st.Minute),
+		int(st.Second),
+		int(st.Milliseconds)*int(time.Millisecond),
+		loc,
+	), nil
+}
+
+// validate returns an error if any of the SYSTEMTIME's fields is out of range.
+func (st SYSTEMTIME) validate() error {
+	switch {
+	case st.Year < 1601 || st.Year > 30827:
+		return fmt.Errorf("invalid SYSTEMTIME year %d", st.Year)
+	case st.Month < 1 || st.Month > 12:
+		return fmt.Errorf("invalid SYSTEMTIME month %d", st.Month)
+	case st.Day < 1 || int(st.Day) > daysIn(time.Month(st.Month), int(st.Year)):
+		return fmt.Errorf("invalid SYSTEMTIME day %d for %04d-%02d", st.Day, st.Year, st.Month)
+	case st.Hour > 23:
+		return fmt.Errorf("invalid SYSTEMTIME hour %d", st.Hour)
+	case st.Minute > 59:
+		return fmt.Errorf("invalid SYSTEMTIME minute %d", st.Minute)
+	case st.Second > 59:
+		return fmt.Errorf("invalid SYSTEMTIME second %d", st.Second)
+	case st.Milliseconds > 999:
+		return fmt.Errorf("invalid SYSTEMTIME milliseconds %d", st.Milliseconds)
+	default:
+		return nil
+	}
+}
+
+// daysIn returns the number of days in the given month of the given year.
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding
+// the SYSTEMTIME in its 16-byte little-endian binary form.
+func (st SYSTEMTIME) MarshalBinary() ([]byte, error) {
+	b := make([]byte, systemTimeSize)
+	for i, field := range []uint16{
+		st.Year, st.Month, st.DayOfWeek, st.Day,
+		st.Hour, st.Minute, st.Second, st.Milliseconds,
+	} {
+		binary.LittleEndian.PutUint16(b[i*2:], field)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding
+// the SYSTEMTIME from its 16-byte little-endian binary form.
+func (st *SYSTEMTIME) UnmarshalBinary(b []byte) error {
+	if len(b) != systemTimeSize {
+		return fmt.Errorf("invalid SYSTEMTIME length: expected %d bytes but got %d", systemTimeSize, len(b))
+	}
+	for i, field := range []*uint16{
+		&st.Year, &st.Month, &st.DayOfWeek, &st.Day,
+		&st.Hour, &st.Minute, &st.Second, &st.Milliseconds,
+	} {
+		*field = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package wintime
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSYSTEMTIMEFromTime(t *testing.T) {
+	tm := time.Date(2024, 2, 29, 13, 14, 15, 987654321, time.FixedZone("UTC+2", 2*60*60))
+	st, err := SYSTEMTIMEFromTime(tm)
+	if err != nil {
+		t.Fatalf("SYSTEMTIMEFromTime failed: %v", err)
+	}
+	want := SYSTEMTIME{Year: 2024, Month: 2, DayOfWeek: 4, Day: 29, Hour: 13, Minute: 14, Second: 15, Milliseconds: 987}
+	if st != want {
+		t.Errorf("SYSTEMTIMEFromTime = %+v, want %+v", st, want)
+	}
+	got, err := st.Time(tm.Location())
+	if err != nil {
+		t.Fatalf("Time failed: %v", err)
+	}
+	if want := tm.Truncate(time.Millisecond); !got.Equal(want) {
+		t.Errorf("Time = %s, want %s", got, want)
+	}
+
+	if st, err := SYSTEMTIMEFromTime(time.Time{}); err != nil || !st.IsZero() {
+		t.Errorf("SYSTEMTIMEFromTime(zero) = %+v, %v, want zero SYSTEMTIME", st, err)
+	}
+	if _, err := SYSTEMTIMEFromTime(time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("SYSTEMTIMEFromTime(1600) succeeded")
+	}
+}
+
+func TestSYSTEMTIMETime(t *testing.T) {
+	st := SYSTEMTIME{Year: 2024, Month: 1, Day: 2, Hour: 3, Minute: 4, Second: 5, Milliseconds: 6}
+	got, err := st.Time(nil)
+	if err != nil {
+		t.Fatalf("Time(nil) failed: %v", err)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("Time(nil) = %s, want %s", got, want)
+	}
+	if got, err := (SYSTEMTIME{}).Time(time.UTC); err != nil || !got.IsZero() {
+		t.Errorf("zero SYSTEMTIME Time = %s, %v, want zero time", got, err)
+	}
+}
+
+func TestSYSTEMTIMETimeInvalid(t *testing.T) {
+	valid := SYSTEMTIME{Year: 2023, Month: 2, Day: 28, Hour: 23, Minute: 59, Second: 59, Milliseconds: 999}
+	if _, err := valid.Time(time.UTC); err != nil {
+		t.Fatalf("Time failed: %v", err)
+	}
+	for name, modify := range map[string]func(*SYSTEMTIME){
+		"year":         func(st *SYSTEMTIME) { st.Year = 1600 },
+		"month":        func(st *SYSTEMTIME) { st.Month = 13 },
+		"zero month":   func(st *SYSTEMTIME) { st.Month = 0 },
+		"day":          func(st *SYSTEMTIME) { st.Day = 29 },
+		"zero day":     func(st *SYSTEMTIME) { st.Day = 0 },
+		"hour":         func(st *SYSTEMTIME) { st.Hour = 24 },
+		"minute":       func(st *SYSTEMTIME) { st.Minute = 60 },
+		"second":       func(st *SYSTEMTIME) { st.Second = 60 },
+		"milliseconds": func(st *SYSTEMTIME) { st.Milliseconds = 1000 },
+	} {
+		st := valid
+		modify(&st)
+		if got, err := st.Time(time.UTC); err == nil {
+			t.Errorf("%s: Time = %s, want error", name, got)
+		}
+	}
+}
+
+func TestSYSTEMTIMEBinary(t *testing.T) {
+	st := SYSTEMTIME{Year: 2024, Month: 1, DayOfWeek: 2, Day: 2, Hour: 3, Minute: 4, Second: 5, Milliseconds: 0x0102}
+	want := []byte{0xE8, 0x07, 1, 0, 2, 0, 2, 0, 3, 0, 4, 0, 5, 0, 0x02, 0x01}
+	b, err := st.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	if !bytes.Equal(b, want) {
+		t.Errorf("MarshalBinary = % x, want % x", b, want)
+	}
+	parsed, err := ParseSYSTEMTIME(b)
+	if err != nil {
+		t.Fatalf("ParseSYSTEMTIME failed: %v", err)
+	}
+	if parsed != st {
+		t.Errorf("ParseSYSTEMTIME = %+v, want %+v", parsed, st)
+	}
+	for _, b := range [][]byte{nil, want[:15], append(want, 0)} {
+		if _, err := ParseSYSTEMTIME(b); err == nil {
+			t.Errorf("ParseSYSTEMTIME of %d bytes succeeded", len(b))
+		}
+	}
+}
@@ -9,9 +9,14 @@ const (
 
 // ToTime converts low and high DWORD bits of a Windows FILETIME or similar
 // format (that represents the number of 100-nanosecond intervals since
-// January 1, 1601 (UTC)) to a time.Time object.
+// January 1, 1601 (UTC)) to a time.Time object in local time. Unset
+// FILETIMEs result in the zero time.Time.
+//
+// Deprecated: use FILETIME.Time instead, which preserves UTC.
 func ToTime(low, high int64) time.Time {
-	full := (high << 32) | (low & 0xFFFFFFFF)              // get a full 64-bit FILETIME
-	unixNano := (full - intervalsBetween1601And1970) * 100 // get unix nanoseconds
-	return time.Unix(0, unixNano)
+	t, ok := FILETIME{LowDateTime: uint32(low), HighDateTime: uint32(high)}.Time()
+	if !ok {
+		return time.Time{}
+	}
+	return t.Local()
 }
@@ -0,0 +1,46 @@
+package wnlm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrReadOnly is returned by the Set* methods of objects obtained from
+	// an INetworkListManager created with NewReadOnlyNetworkListManager.
+	ErrReadOnly = errors.New("read-only")
+)
+
+// ReadOnlyError is the error returned when a mutating method is called on a read-only object.
+type ReadOnlyError struct {
+	Interface string
+	Method    string
+}
+
+// Error implements the error interface.
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s.%s: %s", e.Interface, e.Method, ErrReadOnly)
+}
+
+// Unwrap returns ErrReadOnly, such that errors.Is(err, ErrReadOnly) holds.
+func (e *ReadOnlyError) Unwrap() error {
+	return ErrReadOnly
+}
+
+// ReadOnlyInterceptor is an Interceptor (see InterceptNetworkListManager) which rejects
+// every call to a Set* method with a *ReadOnlyError, without calling the method.
+func ReadOnlyInterceptor(call *Call, next Invoker) {
+	if strings.HasPrefix(call.Method, "Set") {
+		call.Err = &ReadOnlyError{Interface: call.Interface, Method: call.Method}
+		return
+	}
+	next(call)
+}
+
+// NewReadOnlyNetworkListManager returns a read-only view of nlm, whose INetwork objects
+// (however obtained) reject SetName, SetDescription and SetCategory with a *ReadOnlyError.
+// Releasing the returned INetworkListManager releases nlm.
+func NewReadOnlyNetworkListManager(nlm INetworkListManager) INetworkListManager {
+	return InterceptNetworkListManager(nlm, ReadOnlyInterceptor)
+}
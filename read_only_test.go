@@ -0,0 +1,89 @@
+package wnlm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+func TestReadOnlyNetworkListManager(t *testing.T) {
+	m := fake.NewSample()
+	var setterCalls int
+	m.SetFault(func(iface, method string) error {
+		if method == "SetName" || method == "SetDescription" || method == "SetCategory" {
+			setterCalls++
+		}
+		return nil
+	})
+	nlm := wnlm.NewReadOnlyNetworkListManager(m)
+
+	// networks are read-only however they are obtained
+	conns, conn := firstConnection(t, nlm)
+	fromConnection, err := conn.GetNetwork()
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	found, err := wnlm.FindNetwork(nlm, fake.SampleOfficeID)
+	if err != nil {
+		t.Fatalf("FindNetwork failed: %v", err)
+	}
+
+	for _, network := range []wnlm.INetwork{fromConnection, found} {
+		for method, set := range map[string]func() error{
+			"SetName":        func() error { return network.SetName("Name") },
+			"SetDescription": func() error { return network.SetDescription("Description") },
+			"SetCategory":    func() error { return network.SetCategory(wnlm.NLMNetworkCategoryPrivate) },
+		} {
+			err := set()
+			var readOnlyErr *wnlm.ReadOnlyError
+			if !errors.As(err, &readOnlyErr) || !errors.Is(err, wnlm.ErrReadOnly) {
+				t.Errorf("%s error = %v, want a *ReadOnlyError", method, err)
+				continue
+			}
+			if readOnlyErr.Interface != "INetwork" || readOnlyErr.Method != method {
+				t.Errorf("%s error = %+v", method, readOnlyErr)
+			}
+			if want := "INetwork." + method + ": read-only"; err.Error() != want {
+				t.Errorf("%s error = %q, want %q", method, err, want)
+			}
+		}
+		// getters still work
+		if _, err := network.GetName(); err != nil {
+			t.Errorf("GetName failed: %v", err)
+		}
+	}
+	if setterCalls != 0 {
+		t.Errorf("%d setter calls reached the underlying network, want none", setterCalls)
+	}
+	if state, _ := m.Network(fake.SampleHomeID); state.Name != "Home" || state.Category != wnlm.NLMNetworkCategoryPrivate {
+		t.Errorf("network changed to %+v", state)
+	}
+
+	fromConnection.Release()
+	found.Release()
+	conns.Release()
+	nlm.Release()
+	if m.Outstanding() != 0 {
+		t.Errorf("%d outstanding references, want none: releasing the read-only view releases nlm", m.Outstanding())
+	}
+}
+
+func TestReadOnlyInterceptorChained(t *testing.T) {
+	rec := &recorder{}
+	nlm := wnlm.InterceptNetworkListManager(fake.NewSample(), rec.intercept, wnlm.ReadOnlyInterceptor)
+	network, err := wnlm.FindNetwork(nlm, fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("FindNetwork failed: %v", err)
+	}
+	defer network.Release()
+	if err := network.SetName("Name"); !errors.Is(err, wnlm.ErrReadOnly) {
+		t.Fatalf("SetName error = %v, want ErrReadOnly", err)
+	}
+	// outer interceptors see the rejected call
+	last := rec.calls[len(rec.calls)-1]
+	if last.Method != "SetName" || !errors.Is(last.Err, wnlm.ErrReadOnly) {
+		t.Errorf("last call = %s: %v, want the rejected SetName", last.Method, last.Err)
+	}
+}
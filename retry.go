@@ -0,0 +1,514 @@
+package wnlm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// transientHRESULTs are the HRESULTs of failures which may not happen again if the call is retried.
+var transientHRESULTs = []hresult.HRESULT{
+	hresult.RPC_E_CALL_REJECTED,
+	hresult.RPC_E_SERVERCALL_RETRYLATER,
+	hresult.RPC_E_TIMEOUT,
+	hresult.RPC_S_CALL_FAILED,
+}
+
+// serverGoneHRESULTs are the HRESULTs of failures caused by the server of a COM object
+// going away, after which the object must be created again for calls to succeed.
+var serverGoneHRESULTs = []hresult.HRESULT{
+	hresult.RPC_E_DISCONNECTED,
+	hresult.RPC_E_SERVER_DIED,
+	hresult.RPC_E_SERVER_DIED_DNE,
+	hresult.RPC_S_SERVER_UNAVAILABLE,
+	hresult.RPC_S_CALL_FAILED_DNE,
+}
+
+// IsTransient returns true if err is a COM or RPC failure which may not happen again
+// if the call is retried, including those for which IsServerGone returns true.
+func IsTransient(err error) bool {
+	return isAnyHRESULT(err, transientHRESULTs) || IsServerGone(err)
+}
+
+// IsServerGone returns true if err is a COM or RPC failure caused by the server of the
+// object going away (e.g. across a sleep/resume cycle), such that the object has to be
+// created again for calls to succeed.
+func IsServerGone(err error) bool {
+	return isAnyHRESULT(err, serverGoneHRESULTs)
+}
+
+// isAnyHRESULT returns true if err carries any of the given HRESULTs.
+func isAnyHRESULT(err error, hrs []hresult.HRESULT) bool {
+	hr, ok := hresult.FromError(err)
+	if !ok {
+		return false
+	}
+	for _, candidate := range hrs {
+		if hr == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns how long to wait before the given retry (starting at 1) of a call.
+type Backoff func(retry int) time.Duration
+
+// ExponentialBackoff returns a Backoff which waits base before the first retry,
+// doubling the wait for every subsequent retry up to max.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		wait := base
+		for i := 1; i < retry && wait < max; i++ {
+			wait *= 2
+		}
+		return min(wait, max)
+	}
+}
+
+// RetryOption configures an INetworkListManager created with NewRetryingNetworkListManager.
+type RetryOption func(*retryOptions)
+
+// retryOptions holds the configuration set by RetryOptions.
+type retryOptions struct {
+	attempts  int
+	backoff   Backoff
+	retryable func(error) bool
+}
+
+// WithMaxAttempts sets the maximum number of attempts made for each call,
+// including the first one. The default is 4.
+func WithMaxAttempts(attempts int) RetryOption {
+	return func(o *retryOptions) { o.attempts = max(attempts, 1) }
+}
+
+// WithBackoff sets the Backoff between attempts. The default is
+// an ExponentialBackoff from 100 milliseconds up to 2 seconds.
+func WithBackoff(backoff Backoff) RetryOption {
+	return func(o *retryOptions) { o.backoff = backoff }
+}
+
+// WithRetryClassifier sets the function deciding which errors are retried. The default is IsTransient.
+func WithRetryClassifier(retryable func(error) bool) RetryOption {
+	return func(o *retryOptions) { o.retryable = retryable }
+}
+
+// retrier retries failed calls, creating the INetworkListManager they are made to
+// again when its server goes away.
+type retrier struct {
+	options retryOptions
+	create  func() (INetworkListManager, error)
+
+	mu         sync.Mutex
+	nlm        INetworkListManager
+	generation int
+}
+
+// retryingNetworkListManager is an INetworkListManager which retries failed calls,
+// creating its underlying INetworkListManager again when its server goes away.
+type retryingNetworkListManager struct {
+	*retrier
+	// ctx is the context of the calls made through this INetworkListManager (and the
+	// objects obtained from it), set by RetryWithContext.
+	ctx context.Context
+}
+
+// NewRetryingNetworkListManager returns an INetworkListManager which retries calls made
+// to it (and to every INetwork, INetworkConnection and enumeration obtained from it) that
+// fail in a transient way, waiting between attempts as set by WithBackoff.
+//
+// The underlying INetworkListManager is obtained from create, which is called again to
+// replace it whenever a call fails because its server went away (see IsServerGone).
+// Networks and network connections obtained before then are acquired again, by their
+// GUID, from the new INetworkListManager. To that end, the GUID of every object is
+// fetched when the object is obtained.
+//
+// Waiting between attempts to create the first INetworkListManager stops, and
+// NewRetryingNetworkListManager fails, once ctx is done. Calls made later wait between
+// attempts until they run out of them, unless made through RetryWithContext.
+func NewRetryingNetworkListManager(ctx context.Context, create func() (INetworkListManager, error), opts ...RetryOption) (INetworkListManager, error) {
+	options := retryOptions{attempts: 4, backoff: ExponentialBackoff(100*time.Millisecond, 2*time.Second), retryable: IsTransient}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	r := &retrier{options: options, create: create}
+	err := r.retry(ctx, func() error {
+		nlm, err := create()
+		if err != nil {
+			return err
+		}
+		r.nlm = nlm
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &retryingNetworkListManager{retrier: r, ctx: context.Background()}, nil
+}
+
+// RetryWithContext returns an INetworkListManager making its calls to nlm, an
+// INetworkListManager created with NewRetryingNetworkListManager, such that waiting
+// between attempts stops, and the call fails, once ctx is done. The same goes for
+// the calls made to every INetwork, INetworkConnection and enumeration obtained from
+// it. Any other INetworkListManager is returned as is.
+//
+// The returned INetworkListManager shares the underlying INetworkListManager of nlm,
+// so only one of them must be released.
+func RetryWithContext(ctx context.Context, nlm INetworkListManager) INetworkListManager {
+	m, ok := nlm.(*retryingNetworkListManager)
+	if !ok {
+		return nlm
+	}
+	return &retryingNetworkListManager{retrier: m.retrier, ctx: ctx}
+}
+
+// retry calls fn until it succeeds, fails with an error which is not retryable, runs
+// out of attempts, or ctx is done while waiting to retry.
+func (r *retrier) retry(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !r.options.retryable(err) {
+			return err
+		}
+		if attempt >= r.options.attempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		timer := time.NewTimer(r.options.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// current returns the current underlying INetworkListManager and its generation.
+func (r *retrier) current() (INetworkListManager, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.nlm, r.generation
+}
+
+// recreate replaces the underlying INetworkListManager of the given generation, unless
+// it has already been replaced. The replaced INetworkListManager is released.
+func (r *retrier) recreate(generation int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation != generation {
+		return nil
+	}
+	nlm, err := r.create()
+	if err != nil {
+		return err
+	}
+	r.nlm.Release()
+	r.nlm = nlm
+	r.generation++
+	return nil
+}
+
+// retry calls fn as retrier.retry does, with the context of the calls made through m.
+func (m *retryingNetworkListManager) retry(fn func() error) error {
+	return m.retrier.retry(m.ctx, fn)
+}
+
+// GetNetworkConnections returns all network connections for the system.
+func (m *retryingNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	var conns IEnumNetworkConnections
+	var generation int
+	err := m.retry(func() error {
+		var nlm INetworkListManager
+		nlm, generation = m.current()
+		var err error
+		if conns, err = nlm.GetNetworkConnections(); err != nil && IsServerGone(err) {
+			return errors.Join(err, m.recreate(generation))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.networkConnections(conns, generation), nil
+}
+
+// Release releases the INetworkListManager object.
+func (m *retryingNetworkListManager) Release() {
+	nlm, _ := m.current()
+	nlm.Release()
+}
+
+// retryingEnumNetworkConnections is an IEnumNetworkConnections whose INetworkConnection
+// objects retry their calls.
+type retryingEnumNetworkConnections struct {
+	IEnumNetworkConnections
+	// enum is the underlying enumeration, holding the network connections as first obtained.
+	enum IEnumNetworkConnections
+}
+
+// networkConnections returns an IEnumNetworkConnections whose INetworkConnection
+// objects retry their calls, and are acquired again if their server goes away.
+func (m *retryingNetworkListManager) networkConnections(conns IEnumNetworkConnections, generation int) IEnumNetworkConnections {
+	wrapped := make([]INetworkConnection, 0, conns.Size())
+	conns.ForEach(func(_ int, conn INetworkConnection) bool {
+		wrapped = append(wrapped, m.networkConnection(conn, generation))
+		return true
+	})
+	return &retryingEnumNetworkConnections{IEnumNetworkConnections: NewNetworkConnectionsFromSlice(wrapped), enum: conns}
+}
+
+// Release releases the network connections acquired again since the enumeration was
+// obtained, and the underlying enumeration, which releases those first obtained.
+func (e *retryingEnumNetworkConnections) Release() {
+	e.IEnumNetworkConnections.Release()
+	e.enum.Release()
+}
+
+// networkConnection returns an INetworkConnection which retries its calls. The
+// network connection is released along with the enumeration it was obtained from.
+func (m *retryingNetworkListManager) networkConnection(conn INetworkConnection, generation int) INetworkConnection {
+	obj := &retryingObject[INetworkConnection]{manager: m, obj: conn, generation: generation}
+	if id, err := conn.GetConnectionId(); err == nil {
+		obj.reacquire = func(nlm INetworkListManager) (INetworkConnection, error) {
+			return FindNetworkConnection(nlm, id)
+		}
+	}
+	return &retryingNetworkConnection{obj}
+}
+
+// network returns an INetwork which retries its calls.
+func (m *retryingNetworkListManager) network(network INetwork, generation int) INetwork {
+	obj := &retryingObject[INetwork]{manager: m, obj: network, generation: generation, owned: true}
+	if id, err := network.GetNetworkId(); err == nil {
+		obj.reacquire = func(nlm INetworkListManager) (INetwork, error) {
+			return FindNetwork(nlm, id)
+		}
+	}
+	return &retryingNetwork{obj}
+}
+
+// retryingObject is a COM object obtained from a retryingNetworkListManager.
+type retryingObject[T interface{ Release() }] struct {
+	manager *retryingNetworkListManager
+	// reacquire acquires the object from another INetworkListManager, nil if it cannot.
+	reacquire func(INetworkListManager) (T, error)
+
+	mu         sync.Mutex
+	obj        T
+	generation int
+	stale      bool
+	// owned is true if obj is released by the retryingObject, rather than by the
+	// enumeration it was obtained from.
+	owned bool
+}
+
+// current returns the underlying object, and the generation of the INetworkListManager
+// it was obtained from, acquiring it again first if its server went away.
+func (o *retryingObject[T]) current() (T, int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.stale {
+		return o.obj, o.generation, nil
+	}
+	if _, generation := o.manager.current(); generation == o.generation {
+		if err := o.manager.recreate(o.generation); err != nil {
+			return o.obj, o.generation, err
+		}
+	}
+	nlm, generation := o.manager.current()
+	obj, err := o.reacquire(nlm)
+	if err != nil {
+		return o.obj, o.generation, err
+	}
+	if o.owned {
+		o.obj.Release()
+	}
+	o.obj, o.generation, o.stale, o.owned = obj, generation, false, true
+	return o.obj, o.generation, nil
+}
+
+// markStale marks the object as having to be acquired again, if it can be.
+func (o *retryingObject[T]) markStale() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stale = o.reacquire != nil
+}
+
+// Release releases the underlying object, unless it is released by its enumeration.
+func (o *retryingObject[T]) Release() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.owned {
+		o.obj.Release()
+	}
+}
+
+// retryCall calls fn with the underlying object of o, retrying it as needed.
+func retryCall[T interface{ Release() }, R any](o *retryingObject[T], fn func(T) (R, error)) (R, error) {
+	return retryCallGeneration(o, func(obj T, _ int) (R, error) { return fn(obj) })
+}
+
+// retryCallGeneration is like retryCall, but also passes fn the generation of the
+// INetworkListManager the underlying object was obtained from.
+func retryCallGeneration[T interface{ Release() }, R any](o *retryingObject[T], fn func(T, int) (R, error)) (R, error) {
+	var result R
+	err := o.manager.retry(func() error {
+		obj, generation, err := o.current()
+		if err != nil {
+			return err
+		}
+		if result, err = fn(obj, generation); err != nil && IsServerGone(err) {
+			o.markStale()
+		}
+		return err
+	})
+	return result, err
+}
+
+// retryingNetwork is an INetwork which retries its calls.
+type retryingNetwork struct {
+	*retryingObject[INetwork]
+}
+
+// retryingNetworkConnection is an INetworkConnection which retries its calls.
+type retryingNetworkConnection struct {
+	*retryingObject[INetworkConnection]
+}
+
+// noResult adapts a method returning only an error to retryCall.
+func noResult[T any](fn func(T) error) func(T) (struct{}, error) {
+	return func(obj T) (struct{}, error) { return struct{}{}, fn(obj) }
+}
+
+// GetName gets the name of this network.
+func (n *retryingNetwork) GetName() (string, error) {
+	return retryCall(n.retryingObject, INetwork.GetName)
+}
+
+// SetName sets the name of this network.
+func (n *retryingNetwork) SetName(name string) error {
+	_, err := retryCall(n.retryingObject, noResult(func(network INetwork) error { return network.SetName(name) }))
+	return err
+}
+
+// GetDescription gets the description of this network.
+func (n *retryingNetwork) GetDescription() (string, error) {
+	return retryCall(n.retryingObject, INetwork.GetDescription)
+}
+
+// SetDescription sets the description of this network.
+func (n *retryingNetwork) SetDescription(description string) error {
+	_, err := retryCall(n.retryingObject, noResult(func(network INetwork) error { return network.SetDescription(description) }))
+	return err
+}
+
+// GetNetworkId gets the unique identifier of this network.
+func (n *retryingNetwork) GetNetworkId() (GUID, error) {
+	return retryCall(n.retryingObject, INetwork.GetNetworkId)
+}
+
+// GetDomainType gets the domain type of this network.
+func (n *retryingNetwork) GetDomainType() (NLMDomainType, error) {
+	return retryCall(n.retryingObject, INetwork.GetDomainType)
+}
+
+// GetNetworkConnections gets the network connections of this network.
+func (n *retryingNetwork) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	type result struct {
+		conns      IEnumNetworkConnections
+		generation int
+	}
+	res, err := retryCallGeneration(n.retryingObject, func(network INetwork, generation int) (result, error) {
+		conns, err := network.GetNetworkConnections()
+		return result{conns: conns, generation: generation}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return n.manager.networkConnections(res.conns, res.generation), nil
+}
+
+// GetTimeCreatedAndConnected gets the times at which this network was created and last connected to.
+func (n *retryingNetwork) GetTimeCreatedAndConnected() (time.Time, time.Time, error) {
+	times, err := retryCall(n.retryingObject, func(network INetwork) ([2]time.Time, error) {
+		created, connected, err := network.GetTimeCreatedAndConnected()
+		return [2]time.Time{created, connected}, err
+	})
+	return times[0], times[1], err
+}
+
+// IsConnectedToInternet returns true if this network is connected to the internet.
+func (n *retryingNetwork) IsConnectedToInternet() (bool, error) {
+	return retryCall(n.retryingObject, INetwork.IsConnectedToInternet)
+}
+
+// IsConnected returns true if this network is connected.
+func (n *retryingNetwork) IsConnected() (bool, error) {
+	return retryCall(n.retryingObject, INetwork.IsConnected)
+}
+
+// GetConnectivity gets the connectivity of this network.
+func (n *retryingNetwork) GetConnectivity() (NLMConnectivity, error) {
+	return retryCall(n.retryingObject, INetwork.GetConnectivity)
+}
+
+// GetCategory gets the category of this network.
+func (n *retryingNetwork) GetCategory() (NLMNetworkCategory, error) {
+	return retryCall(n.retryingObject, INetwork.GetCategory)
+}
+
+// SetCategory sets the category of this network.
+func (n *retryingNetwork) SetCategory(category NLMNetworkCategory) error {
+	_, err := retryCall(n.retryingObject, noResult(func(network INetwork) error { return network.SetCategory(category) }))
+	return err
+}
+
+// GetNetwork gets the network of this network connection.
+func (nc *retryingNetworkConnection) GetNetwork() (INetwork, error) {
+	type result struct {
+		network    INetwork
+		generation int
+	}
+	res, err := retryCallGeneration(nc.retryingObject, func(conn INetworkConnection, generation int) (result, error) {
+		network, err := conn.GetNetwork()
+		return result{network: network, generation: generation}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nc.manager.network(res.network, res.generation), nil
+}
+
+// IsConnectedToInternet returns true if this network connection is connected to the internet.
+func (nc *retryingNetworkConnection) IsConnectedToInternet() (bool, error) {
+	return retryCall(nc.retryingObject, INetworkConnection.IsConnectedToInternet)
+}
+
+// IsConnected returns true if this network connection is connected.
+func (nc *retryingNetworkConnection) IsConnected() (bool, error) {
+	return retryCall(nc.retryingObject, INetworkConnection.IsConnected)
+}
+
+// GetConnectivity gets the connectivity of this network connection.
+func (nc *retryingNetworkConnection) GetConnectivity() (NLMConnectivity, error) {
+	return retryCall(nc.retryingObject, INetworkConnection.GetConnectivity)
+}
+
+// GetConnectionId gets the unique identifier of this network connection.
+func (nc *retryingNetworkConnection) GetConnectionId() (GUID, error) {
+	return retryCall(nc.retryingObject, INetworkConnection.GetConnectionId)
+}
+
+// GetAdapterId gets the unique identifier of the network adapter of this network connection.
+func (nc *retryingNetworkConnection) GetAdapterId() (GUID, error) {
+	return retryCall(nc.retryingObject, INetworkConnection.GetAdapterId)
+}
+
+// GetDomainType gets the domain type of this network connection.
+func (nc *retryingNetworkConnection) GetDomainType() (NLMDomainType, error) {
+	return retryCall(nc.retryingObject, INetworkConnection.GetDomainType)
+}
@@ -0,0 +1,279 @@
+package wnlm_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+	"github.com/adrianosela/wnlm/pkg/hresult"
+)
+
+// failingCalls is a fake.Fault failing calls to a given method with an error,
+// a given number of times, and counting the calls made to it.
+type failingCalls struct {
+	mu       sync.Mutex
+	method   string
+	err      error
+	failures int
+	calls    int
+}
+
+func (f *failingCalls) fault(_, method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if method != f.method {
+		return nil
+	}
+	f.calls++
+	if f.failures == 0 {
+		return nil
+	}
+	f.failures--
+	return f.err
+}
+
+// backoffs is a wnlm.Backoff recording the retries it is called for, which does not wait.
+type backoffs struct {
+	mu      sync.Mutex
+	retries []int
+}
+
+func (b *backoffs) backoff(retry int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.retries = append(b.retries, retry)
+	return 0
+}
+
+// newRetrying returns a retrying INetworkListManager of m, released at the end of the test.
+func newRetrying(t *testing.T, m wnlm.INetworkListManager, opts ...wnlm.RetryOption) wnlm.INetworkListManager {
+	t.Helper()
+	nlm, err := wnlm.NewRetryingNetworkListManager(context.Background(), func() (wnlm.INetworkListManager, error) { return m, nil }, opts...)
+	if err != nil {
+		t.Fatalf("NewRetryingNetworkListManager failed: %v", err)
+	}
+	t.Cleanup(nlm.Release)
+	return nlm
+}
+
+func TestRetryTransient(t *testing.T) {
+	m := fake.NewSample()
+	calls := &failingCalls{method: "GetNetworkConnections", err: hresult.RPC_E_CALL_REJECTED, failures: 2}
+	m.SetFault(calls.fault)
+	b := &backoffs{}
+	nlm := newRetrying(t, m, wnlm.WithBackoff(b.backoff))
+
+	conns, err := nlm.GetNetworkConnections()
+	if err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+	conns.Release()
+	if calls.calls != 3 {
+		t.Errorf("GetNetworkConnections called %d times, want 3", calls.calls)
+	}
+	if !reflect.DeepEqual(b.retries, []int{1, 2}) {
+		t.Errorf("backoff called for retries %v, want [1 2]", b.retries)
+	}
+
+	// calls to objects obtained from the manager are retried too
+	calls = &failingCalls{method: "GetName", err: hresult.RPC_E_CALL_REJECTED, failures: 1}
+	m.SetFault(calls.fault)
+	network, err := wnlm.FindNetwork(nlm, fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("FindNetwork failed: %v", err)
+	}
+	defer network.Release()
+	if name, err := network.GetName(); err != nil || name != "Home" {
+		t.Errorf("GetName() = %q, %v, want Home", name, err)
+	}
+	if calls.calls != 2 {
+		t.Errorf("GetName called %d times, want 2", calls.calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	m := fake.NewSample()
+	calls := &failingCalls{method: "GetNetworkConnections", err: hresult.RPC_E_CALL_REJECTED, failures: -1}
+	m.SetFault(calls.fault)
+	b := &backoffs{}
+	nlm := newRetrying(t, m, wnlm.WithBackoff(b.backoff), wnlm.WithMaxAttempts(3))
+
+	_, err := nlm.GetNetworkConnections()
+	if !errors.Is(err, hresult.RPC_E_CALL_REJECTED) || !strings.Contains(err.Error(), "giving up after 3 attempts") {
+		t.Errorf("GetNetworkConnections() = %v, want RPC_E_CALL_REJECTED after 3 attempts", err)
+	}
+	if calls.calls != 3 {
+		t.Errorf("GetNetworkConnections called %d times, want 3", calls.calls)
+	}
+	if !reflect.DeepEqual(b.retries, []int{1, 2}) {
+		t.Errorf("backoff called for retries %v, want [1 2]", b.retries)
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	m := fake.NewSample()
+	calls := &failingCalls{method: "GetNetworkConnections", err: hresult.E_ACCESSDENIED, failures: 1}
+	m.SetFault(calls.fault)
+	b := &backoffs{}
+	nlm := newRetrying(t, m, wnlm.WithBackoff(b.backoff))
+
+	if _, err := nlm.GetNetworkConnections(); !errors.Is(err, hresult.E_ACCESSDENIED) {
+		t.Errorf("GetNetworkConnections() = %v, want E_ACCESSDENIED", err)
+	}
+	if calls.calls != 1 || len(b.retries) != 0 {
+		t.Errorf("GetNetworkConnections called %d times with %d backoffs, want 1 and none", calls.calls, len(b.retries))
+	}
+
+	// which errors are retried can be changed
+	calls = &failingCalls{method: "GetNetworkConnections", err: hresult.E_ACCESSDENIED, failures: 1}
+	m.SetFault(calls.fault)
+	nlm = newRetrying(t, m, wnlm.WithBackoff(b.backoff), wnlm.WithRetryClassifier(func(err error) bool {
+		return errors.Is(err, hresult.E_ACCESSDENIED)
+	}))
+	conns, err := nlm.GetNetworkConnections()
+	if err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+	conns.Release()
+	if calls.calls != 2 {
+		t.Errorf("GetNetworkConnections called %d times, want 2", calls.calls)
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	m := fake.NewSample()
+	calls := &failingCalls{method: "GetNetworkConnections", err: hresult.RPC_E_CALL_REJECTED, failures: -1}
+	m.SetFault(calls.fault)
+	ctx, cancel := context.WithCancel(context.Background())
+	// the context is canceled while waiting for the first retry, which would take an hour otherwise
+	nlm := newRetrying(t, m, wnlm.WithBackoff(func(int) time.Duration {
+		cancel()
+		return time.Hour
+	}))
+
+	_, err := wnlm.RetryWithContext(ctx, nlm).GetNetworkConnections()
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, hresult.RPC_E_CALL_REJECTED) {
+		t.Errorf("GetNetworkConnections() = %v, want context.Canceled after RPC_E_CALL_REJECTED", err)
+	}
+	if calls.calls != 1 {
+		t.Errorf("GetNetworkConnections called %d times, want 1", calls.calls)
+	}
+
+	// objects obtained with a context keep using it
+	m.SetFault(nil)
+	conns, conn := firstConnection(t, wnlm.RetryWithContext(ctx, nlm))
+	defer conns.Release()
+	m.SetFault((&failingCalls{method: "GetConnectivity", err: hresult.RPC_E_CALL_REJECTED, failures: -1}).fault)
+	if _, err := conn.GetConnectivity(); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetConnectivity() = %v, want context.Canceled", err)
+	}
+
+	// creating the manager stops retrying once the context of the call is done
+	_, err = wnlm.NewRetryingNetworkListManager(ctx, func() (wnlm.INetworkListManager, error) {
+		return nil, hresult.RPC_S_SERVER_UNAVAILABLE
+	}, wnlm.WithBackoff(func(int) time.Duration { return time.Hour }))
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, hresult.RPC_S_SERVER_UNAVAILABLE) {
+		t.Errorf("NewRetryingNetworkListManager() = %v, want context.Canceled after RPC_S_SERVER_UNAVAILABLE", err)
+	}
+
+	if wnlm.RetryWithContext(ctx, m) != wnlm.INetworkListManager(m) {
+		t.Error("RetryWithContext() of a manager which does not retry = a new manager, want it as is")
+	}
+}
+
+func TestRetryRecreate(t *testing.T) {
+	first, second := fake.NewSample(), fake.NewSample()
+	second.UpdateNetwork(fake.SampleHomeID, func(n *fake.Network) { n.Name = "Home (again)" })
+	second.UpdateConnection(fake.SampleWiFiID, func(c *fake.Connection) { c.Connectivity = wnlm.NLMConnectivityIPv4LocalNetwork })
+	managers := []*fake.NetworkListManager{first, second}
+	created := 0
+	b := &backoffs{}
+	nlm, err := wnlm.NewRetryingNetworkListManager(context.Background(), func() (wnlm.INetworkListManager, error) {
+		if created == len(managers) {
+			t.Fatal("manager created more than twice")
+		}
+		created++
+		return managers[created-1], nil
+	}, wnlm.WithBackoff(b.backoff))
+	if err != nil {
+		t.Fatalf("NewRetryingNetworkListManager failed: %v", err)
+	}
+
+	network, err := wnlm.FindNetwork(nlm, fake.SampleHomeID)
+	if err != nil {
+		t.Fatalf("FindNetwork failed: %v", err)
+	}
+	conn, err := wnlm.FindNetworkConnection(nlm, fake.SampleWiFiID)
+	if err != nil {
+		t.Fatalf("FindNetworkConnection failed: %v", err)
+	}
+
+	// the server of the first manager goes away
+	first.SetFault(func(string, string) error { return hresult.RPC_S_SERVER_UNAVAILABLE })
+	if name, err := network.GetName(); err != nil || name != "Home (again)" {
+		t.Errorf("GetName() = %q, %v, want the name from the second manager", name, err)
+	}
+	if created != 2 {
+		t.Errorf("manager created %d times, want 2", created)
+	}
+	if connectivity, err := conn.GetConnectivity(); err != nil || connectivity != wnlm.NLMConnectivityIPv4LocalNetwork {
+		t.Errorf("GetConnectivity() = %v, %v, want the connectivity from the second manager", connectivity, err)
+	}
+	if id, err := conn.GetConnectionId(); err != nil || id != fake.SampleWiFiID {
+		t.Errorf("GetConnectionId() = %v, %v, want %v", id, err, fake.SampleWiFiID)
+	}
+	if !reflect.DeepEqual(b.retries, []int{1, 1}) {
+		t.Errorf("backoff called for retries %v, want [1 1]", b.retries)
+	}
+
+	// the objects of the first manager were released as they were acquired again, and the manager with them
+	if first.Outstanding() != 2 {
+		t.Errorf("first manager has %d outstanding references, want the 2 network connections of the enumeration", first.Outstanding())
+	}
+	network.Release()
+	conn.Release()
+	nlm.Release()
+	for i, m := range managers {
+		if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+			t.Errorf("manager %d has %d outstanding references and %d double releases, want none", i+1, m.Outstanding(), m.DoubleReleases())
+		}
+	}
+}
+
+func TestRetryReleasesEnumerations(t *testing.T) {
+	tracker := wnlm.NewTracker()
+	m := fake.NewSample()
+	nlm, err := wnlm.NewRetryingNetworkListManager(context.Background(), func() (wnlm.INetworkListManager, error) {
+		return tracker.TrackNetworkListManager(m), nil
+	})
+	if err != nil {
+		t.Fatalf("NewRetryingNetworkListManager failed: %v", err)
+	}
+
+	conns, conn := firstConnection(t, nlm)
+	network, err := conn.GetNetwork()
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	networkConns, err := network.GetNetworkConnections()
+	if err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+	networkConns.Release()
+	network.Release()
+	conns.Release()
+	nlm.Release()
+
+	if err := tracker.Check(); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("fake has %d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+}
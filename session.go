@@ -0,0 +1,349 @@
+package wnlm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/adrianosela/wnlm/pkg/executor"
+)
+
+var (
+	// ErrSessionClosed is returned when using a Session (or an object
+	// obtained from it) after the Session has been closed.
+	ErrSessionClosed = errors.New("session closed")
+)
+
+// SessionError is the error returned when a Session is misused, such as
+// when it is used after being closed.
+type SessionError struct {
+	Op    string
+	State SessionState
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *SessionError) Error() string {
+	return fmt.Sprintf("session %s: %s (session is %s)", e.Op, e.Err, e.State)
+}
+
+// Unwrap returns the underlying error, such that errors.Is(err, ErrSessionClosed) holds.
+func (e *SessionError) Unwrap() error {
+	return e.Err
+}
+
+// ApartmentModel represents a COM apartment (concurrency) model.
+//
+// https://learn.microsoft.com/en-us/windows/win32/com/processes--threads--and-apartments
+type ApartmentModel int
+
+const (
+	// ApartmentSTA represents the single-threaded apartment model. Objects live on a
+	// single thread and every call made to them is marshalled onto that thread.
+	ApartmentSTA = ApartmentModel(0)
+	// ApartmentMTA represents the multithreaded apartment model. Objects can be
+	// called from any thread, which avoids marshalling calls onto a single thread.
+	ApartmentMTA = ApartmentModel(1)
+)
+
+var apartmentModelToString = map[ApartmentModel]string{
+	ApartmentSTA: "STA",
+	ApartmentMTA: "MTA",
+}
+
+// String returns the string representation of the ApartmentModel.
+func (m ApartmentModel) String() string {
+	if str, ok := apartmentModelToString[m]; ok {
+		return str
+	}
+	return ""
+}
+
+// SessionState represents the lifecycle state of a Session.
+type SessionState int
+
+const (
+	// SessionStateOpen represents a Session which can be used.
+	SessionStateOpen = SessionState(0)
+	// SessionStateClosing represents a Session which is releasing its objects.
+	SessionStateClosing = SessionState(1)
+	// SessionStateClosed represents a Session which has been closed.
+	SessionStateClosed = SessionState(2)
+)
+
+var sessionStateToString = map[SessionState]string{
+	SessionStateOpen:    "open",
+	SessionStateClosing: "closing",
+	SessionStateClosed:  "closed",
+}
+
+// String returns the string representation of the SessionState.
+func (s SessionState) String() string {
+	if str, ok := sessionStateToString[s]; ok {
+		return str
+	}
+	return ""
+}
+
+// ManagerFactory creates INetworkListManager objects.
+type ManagerFactory func(...ManagerOption) (INetworkListManager, error)
+
+// SessionOption configures a Session created with NewSession.
+type SessionOption func(*sessionOptions)
+
+// sessionOptions holds the configuration set by SessionOptions.
+type sessionOptions struct {
+	apartment ApartmentModel
+	factory   ManagerFactory
+	tracker   *Tracker
+}
+
+// WithApartment sets the COM apartment model of a Session. The default is ApartmentSTA.
+func WithApartment(model ApartmentModel) SessionOption {
+	return func(o *sessionOptions) { o.apartment = model }
+}
+
+// WithManagerFactory overrides how a Session creates INetworkListManager objects,
+// e.g. to use a fake implementation on platforms other than Windows.
+func WithManagerFactory(factory ManagerFactory) SessionOption {
+	return func(o *sessionOptions) { o.factory = factory }
+}
+
+// WithLeakTracking records every object obtained from the Session's INetworkListManager
+// objects in the given Tracker (see Tracker for details). When the Session is closed,
+// objects other than INetworkListManager objects which were not released yet are
+// released before COM is uninitialized, and Close returns a *LeakError listing them.
+// A Tracker should not be shared by more than one Session.
+func WithLeakTracking(tracker *Tracker) SessionOption {
+	return func(o *sessionOptions) { o.tracker = tracker }
+}
+
+// apartment is a reference counted COM apartment, owned by a dedicated executor thread.
+type apartment struct {
+	model ApartmentModel
+	exec  *executor.Executor
+	refs  int
+}
+
+// apartments holds the apartments in use by open Sessions, by model.
+var apartments = struct {
+	sync.Mutex
+	byModel map[ApartmentModel]*apartment
+}{byModel: make(map[ApartmentModel]*apartment)}
+
+// acquireApartment returns the apartment for the given model, starting it if
+// no other Session is using it, and increments its reference count.
+func acquireApartment(model ApartmentModel) (*apartment, error) {
+	apartments.Lock()
+	defer apartments.Unlock()
+
+	if apt, ok := apartments.byModel[model]; ok {
+		apt.refs++
+		return apt, nil
+	}
+	exec, err := startApartment(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize COM %s apartment: %w", model, err)
+	}
+	apt := &apartment{model: model, exec: exec, refs: 1}
+	apartments.byModel[model] = apt
+	return apt, nil
+}
+
+// release decrements the apartment's reference count, stopping
+// it (and uninitializing COM) when it is no longer in use.
+func (a *apartment) release() {
+	apartments.Lock()
+	defer apartments.Unlock()
+
+	a.refs--
+	if a.refs > 0 {
+		return
+	}
+	delete(apartments.byModel, a.model)
+	a.exec.Close()
+}
+
+// Session is a reference counted initialization of COM, in a chosen apartment
+// model, which owns the INetworkListManager objects created from it.
+//
+// Sessions replace the process-wide Initialize and Uninitialize functions:
+// any number of Sessions (e.g. one per library) can be open at once, and COM
+// is only uninitialized once the last Session of an apartment model is closed.
+type Session struct {
+	mu       sync.Mutex
+	state    SessionState
+	apt      *apartment
+	factory  ManagerFactory
+	tracker  *Tracker
+	managers []*sessionNetworkListManager
+}
+
+// NewSession initializes COM in the chosen apartment model (ApartmentSTA
+// unless set with WithApartment) and returns a new Session.
+func NewSession(opts ...SessionOption) (*Session, error) {
+	options := sessionOptions{apartment: ApartmentSTA, factory: defaultManagerFactory}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if _, ok := apartmentModelToString[options.apartment]; !ok {
+		return nil, fmt.Errorf("invalid apartment model %d", options.apartment)
+	}
+	apt, err := acquireApartment(options.apartment)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{state: SessionStateOpen, apt: apt, factory: options.factory, tracker: options.tracker}, nil
+}
+
+// Apartment returns the COM apartment model of the Session.
+func (s *Session) Apartment() ApartmentModel {
+	return s.apt.model
+}
+
+// State returns the lifecycle state of the Session.
+func (s *Session) State() SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// NewNetworkListManager creates a new INetworkListManager owned by the Session.
+//
+// In the STA model, the returned object (and every object obtained from it) is
+// goroutine-safe, as all calls are marshalled onto the apartment's thread. In the
+// MTA model, calls are made directly from the calling goroutine's thread.
+func (s *Session) NewNetworkListManager(opts ...ManagerOption) (INetworkListManager, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state != SessionStateOpen {
+		return nil, &SessionError{Op: "NewNetworkListManager", State: s.state, Err: ErrSessionClosed}
+	}
+	nlm, err := executor.Call(s.apt.exec, func() (INetworkListManager, error) { return s.factory(opts...) })
+	if err != nil {
+		return nil, err
+	}
+	if s.apt.model == ApartmentSTA {
+		nlm = NewExecutorNetworkListManager(s.apt.exec, nlm)
+	}
+	if s.tracker != nil {
+		nlm = s.tracker.TrackNetworkListManager(nlm)
+	}
+	nlm = InterceptNetworkListManager(nlm, s.intercept)
+	managed := &sessionNetworkListManager{session: s, nlm: nlm}
+	s.managers = append(s.managers, managed)
+	return managed, nil
+}
+
+// Close releases every INetworkListManager created from the Session which has
+// not been released yet, in reverse order of creation, and then releases the
+// Session's reference to COM. Closing a Session more than once is an error.
+//
+// With leak tracking enabled (see WithLeakTracking), Close also releases any
+// other outstanding tracked objects first, and returns a *LeakError if there
+// were any, or if any object was released more than once, after closing.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	if s.state != SessionStateOpen {
+		state := s.state
+		s.mu.Unlock()
+		return &SessionError{Op: "Close", State: state, Err: ErrSessionClosed}
+	}
+	s.state = SessionStateClosing
+	managers := s.managers
+	s.managers = nil
+	s.mu.Unlock()
+
+	var leaked []TrackedObject
+	if s.tracker != nil {
+		leaked = s.tracker.releaseOutstanding(func(obj TrackedObject) bool {
+			return obj.Interface != "INetworkListManager"
+		})
+	}
+	for i := len(managers) - 1; i >= 0; i-- {
+		managers[i].release()
+	}
+	s.apt.release()
+
+	s.mu.Lock()
+	s.state = SessionStateClosed
+	s.mu.Unlock()
+
+	if s.tracker != nil {
+		if doubleReleases := s.tracker.DoubleReleases(); len(leaked) > 0 || len(doubleReleases) > 0 {
+			return &LeakError{Outstanding: leaked, DoubleReleases: doubleReleases}
+		}
+	}
+	return nil
+}
+
+// intercept fails calls made to the INetwork and INetworkConnection objects obtained
+// from the Session's INetworkListManager objects once the Session is closed, or
+// which fail because the Session's apartment is gone, with a *SessionError.
+// Releasing objects is always allowed.
+func (s *Session) intercept(call *Call, next Invoker) {
+	if call.Interface == "INetworkListManager" || call.Interface == "IEnumNetworkConnections" || call.Method == "Release" {
+		next(call)
+		return
+	}
+	if state := s.State(); state == SessionStateClosed {
+		call.Err = &SessionError{Op: call.Method, State: state, Err: ErrSessionClosed}
+		return
+	}
+	next(call)
+	if errors.Is(call.Err, executor.ErrClosed) {
+		call.Err = &SessionError{Op: call.Method, State: s.State(), Err: ErrSessionClosed}
+	}
+}
+
+// forget removes an INetworkListManager released by its owner from the Session.
+func (s *Session) forget(nlm *sessionNetworkListManager) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, managed := range s.managers {
+		if managed == nlm {
+			s.managers = append(s.managers[:i], s.managers[i+1:]...)
+			return
+		}
+	}
+}
+
+// sessionNetworkListManager is an INetworkListManager owned by a Session.
+type sessionNetworkListManager struct {
+	session  *Session
+	mu       sync.RWMutex
+	nlm      INetworkListManager
+	released bool
+}
+
+// GetNetworkConnections returns all network connections for the system.
+func (m *sessionNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.released {
+		return nil, &SessionError{Op: "GetNetworkConnections", State: m.session.State(), Err: ErrSessionClosed}
+	}
+	return m.nlm.GetNetworkConnections()
+}
+
+// Release releases the INetworkListManager object. Releasing it more than once,
+// or after its Session has been closed, has no effect.
+func (m *sessionNetworkListManager) Release() {
+	if m.release() {
+		m.session.forget(m)
+	}
+}
+
+// release releases the underlying INetworkListManager, waiting for in-flight calls
+// to complete, and returns false if it had already been released.
+func (m *sessionNetworkListManager) release() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.released {
+		return false
+	}
+	m.released = true
+	m.nlm.Release()
+	return true
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package wnlm
+
+import (
+	"errors"
+
+	"github.com/adrianosela/wnlm/pkg/executor"
+)
+
+var (
+	// ErrUnsupportedPlatform is returned when creating Windows objects on other platforms.
+	ErrUnsupportedPlatform = errors.New("the Network List Manager API is only available on Windows")
+)
+
+// defaultManagerFactory is the ManagerFactory used by Sessions unless overridden.
+var defaultManagerFactory ManagerFactory = func(...ManagerOption) (INetworkListManager, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// startApartment starts a dedicated thread for the given apartment model. There
+// is no COM outside of Windows, so the thread only serializes calls.
+func startApartment(ApartmentModel) (*executor.Executor, error) {
+	return executor.New(nil, nil)
+}
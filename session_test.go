@@ -0,0 +1,166 @@
+package wnlm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+// firstConnection returns the first network connection of nlm.
+func firstConnection(t *testing.T, nlm wnlm.INetworkListManager) (wnlm.IEnumNetworkConnections, wnlm.INetworkConnection) {
+	t.Helper()
+	conns, err := nlm.GetNetworkConnections()
+	if err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+	var first wnlm.INetworkConnection
+	conns.ForEach(func(_ int, conn wnlm.INetworkConnection) bool {
+		first = conn
+		return false
+	})
+	if first == nil {
+		t.Fatalf("no network connections")
+	}
+	return conns, first
+}
+
+// assertSessionClosed fails the test unless err is a *SessionError for a closed Session.
+func assertSessionClosed(t *testing.T, err error, op string) {
+	t.Helper()
+	var sessionErr *wnlm.SessionError
+	if !errors.As(err, &sessionErr) || !errors.Is(err, wnlm.ErrSessionClosed) {
+		t.Fatalf("%s error = %v, want a *SessionError for a closed session", op, err)
+	}
+	if sessionErr.Op != op || sessionErr.State != wnlm.SessionStateClosed {
+		t.Errorf("%s error = %+v, want Op %s in the closed state", op, sessionErr, op)
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	m := fake.NewSample()
+	session, err := wnlm.NewSession(wnlm.WithManagerFactory(factory(m)))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	if session.State() != wnlm.SessionStateOpen || session.Apartment() != wnlm.ApartmentSTA {
+		t.Errorf("new Session is %s in %s, want open in STA", session.State(), session.Apartment())
+	}
+	nlm, err := session.NewNetworkListManager()
+	if err != nil {
+		t.Fatalf("NewNetworkListManager failed: %v", err)
+	}
+	if _, err := nlm.GetNetworkConnections(); err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if session.State() != wnlm.SessionStateClosed {
+		t.Errorf("State() = %s, want closed", session.State())
+	}
+	assertSessionClosed(t, session.Close(), "Close")
+	_, err = session.NewNetworkListManager()
+	assertSessionClosed(t, err, "NewNetworkListManager")
+	_, err = nlm.GetNetworkConnections()
+	assertSessionClosed(t, err, "GetNetworkConnections")
+
+	// the manager, and the connections left unreleased, were released on Close
+	nlm.Release()
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("%d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+}
+
+func TestSessionClosedObjects(t *testing.T) {
+	for _, model := range []wnlm.ApartmentModel{wnlm.ApartmentSTA, wnlm.ApartmentMTA} {
+		t.Run(model.String(), func(t *testing.T) {
+			session, err := wnlm.NewSession(wnlm.WithApartment(model), wnlm.WithManagerFactory(factory(fake.NewSample())))
+			if err != nil {
+				t.Fatalf("NewSession failed: %v", err)
+			}
+			nlm, err := session.NewNetworkListManager()
+			if err != nil {
+				t.Fatalf("NewNetworkListManager failed: %v", err)
+			}
+			conns, conn := firstConnection(t, nlm)
+			defer conns.Release()
+			network, err := conn.GetNetwork()
+			if err != nil {
+				t.Fatalf("GetNetwork failed: %v", err)
+			}
+			defer network.Release()
+			if id, err := conn.GetConnectionId(); err != nil || id != fake.SampleWiFiID {
+				t.Fatalf("GetConnectionId = %s, %v, want %s", id, err, fake.SampleWiFiID)
+			}
+
+			if err := session.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			_, err = conn.GetConnectionId()
+			assertSessionClosed(t, err, "GetConnectionId")
+			_, err = conn.GetNetwork()
+			assertSessionClosed(t, err, "GetNetwork")
+			_, err = network.GetName()
+			assertSessionClosed(t, err, "GetName")
+			assertSessionClosed(t, network.SetName("name"), "SetName")
+		})
+	}
+}
+
+func TestSessionReleaseManager(t *testing.T) {
+	m := fake.NewSample()
+	session, err := wnlm.NewSession(wnlm.WithManagerFactory(factory(m)))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	defer session.Close()
+	nlm, err := session.NewNetworkListManager()
+	if err != nil {
+		t.Fatalf("NewNetworkListManager failed: %v", err)
+	}
+	nlm.Release()
+	nlm.Release()
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("%d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+	var sessionErr *wnlm.SessionError
+	if _, err := nlm.GetNetworkConnections(); !errors.As(err, &sessionErr) || sessionErr.State != wnlm.SessionStateOpen {
+		t.Errorf("GetNetworkConnections on a released manager error = %v, want a *SessionError", err)
+	}
+}
+
+func TestSessionSharedApartment(t *testing.T) {
+	first, err := wnlm.NewSession(wnlm.WithManagerFactory(factory(fake.NewSample())))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	second, err := wnlm.NewSession(wnlm.WithManagerFactory(factory(fake.NewSample())))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	nlm, err := second.NewNetworkListManager()
+	if err != nil {
+		t.Fatalf("NewNetworkListManager failed: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	// the apartment is still in use by the second Session
+	conns, conn := firstConnection(t, nlm)
+	defer conns.Release()
+	if _, err := conn.GetConnectivity(); err != nil {
+		t.Errorf("GetConnectivity after closing another Session failed: %v", err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func TestSessionInvalidApartment(t *testing.T) {
+	if _, err := wnlm.NewSession(wnlm.WithApartment(wnlm.ApartmentModel(7))); err == nil {
+		t.Errorf("NewSession with an invalid apartment model succeeded")
+	}
+}
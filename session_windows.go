@@ -0,0 +1,20 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"github.com/adrianosela/wnlm/pkg/executor"
+	"github.com/go-ole/go-ole"
+)
+
+// defaultManagerFactory is the ManagerFactory used by Sessions unless overridden.
+var defaultManagerFactory ManagerFactory = NewNetworkListManager
+
+// startApartment starts a dedicated thread initialized in the given COM apartment model.
+func startApartment(model ApartmentModel) (*executor.Executor, error) {
+	coinit := uint32(ole.COINIT_APARTMENTTHREADED)
+	if model == ApartmentMTA {
+		coinit = ole.COINIT_MULTITHREADED
+	}
+	return executor.New(func() error { return coInitializeEx(coinit) }, ole.CoUninitialize)
+}
@@ -0,0 +1,183 @@
+package wnlm
+
+import (
+	"fmt"
+	"time"
+)
+
+// NetworkInfo is the state of a network at a point in time.
+type NetworkInfo struct {
+	ID                  GUID               `json:"id"`
+	Name                string             `json:"name"`
+	Description         string             `json:"description"`
+	Category            NLMNetworkCategory `json:"category"`
+	DomainType          NLMDomainType      `json:"domainType"`
+	Connectivity        NLMConnectivity    `json:"connectivity"`
+	Connected           bool               `json:"connected"`
+	ConnectedToInternet bool               `json:"connectedToInternet"`
+	Created             time.Time          `json:"created"`
+	LastConnected       time.Time          `json:"lastConnected"`
+	Connections         []GUID             `json:"connections"`
+}
+
+// ConnectionInfo is the state of a network connection at a point in time.
+type ConnectionInfo struct {
+	ID                  GUID            `json:"id"`
+	AdapterID           GUID            `json:"adapterId"`
+	NetworkID           GUID            `json:"networkId"`
+	DomainType          NLMDomainType   `json:"domainType"`
+	Connectivity        NLMConnectivity `json:"connectivity"`
+	Connected           bool            `json:"connected"`
+	ConnectedToInternet bool            `json:"connectedToInternet"`
+}
+
+// Snapshot is the state of every network and network connection at a point in time.
+type Snapshot struct {
+	Time        time.Time        `json:"time"`
+	Networks    []NetworkInfo    `json:"networks"`
+	Connections []ConnectionInfo `json:"connections"`
+}
+
+// TakeSnapshot returns the state of every network connection, and of the network of each,
+// as seen through nlm. Since networks are found through their connections, networks
+// without any connections are not part of the Snapshot. Networks and connections are
+// in the order they are enumerated by nlm.
+func TakeSnapshot(nlm INetworkListManager) (*Snapshot, error) {
+	conns, err := nlm.GetNetworkConnections()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network connections: %w", err)
+	}
+	defer conns.Release()
+
+	snapshot := &Snapshot{Time: time.Now(), Networks: []NetworkInfo{}, Connections: []ConnectionInfo{}}
+	networks := map[GUID]int{}
+	conns.ForEach(func(_ int, conn INetworkConnection) bool {
+		err = snapshot.add(conn, networks)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Network returns the network with the given GUID.
+func (s *Snapshot) Network(id GUID) (NetworkInfo, bool) {
+	for _, network := range s.Networks {
+		if network.ID == id {
+			return network, true
+		}
+	}
+	return NetworkInfo{}, false
+}
+
+// Connection returns the network connection with the given GUID.
+func (s *Snapshot) Connection(id GUID) (ConnectionInfo, bool) {
+	for _, conn := range s.Connections {
+		if conn.ID == id {
+			return conn, true
+		}
+	}
+	return ConnectionInfo{}, false
+}
+
+// ConnectionsOf returns the network connections of the network with the given GUID.
+func (s *Snapshot) ConnectionsOf(networkID GUID) []ConnectionInfo {
+	conns := []ConnectionInfo{}
+	for _, conn := range s.Connections {
+		if conn.NetworkID == networkID {
+			conns = append(conns, conn)
+		}
+	}
+	return conns
+}
+
+// add adds the state of a network connection, and of its network unless already in
+// the Snapshot at the index held in networks, to the Snapshot.
+func (s *Snapshot) add(conn INetworkConnection, networks map[GUID]int) error {
+	info, err := connectionInfo(conn)
+	if err != nil {
+		return err
+	}
+	network, err := conn.GetNetwork()
+	if err != nil {
+		return fmt.Errorf("failed to get INetwork for INetworkConnection %s: %w", info.ID, err)
+	}
+	defer network.Release()
+	if info.NetworkID, err = network.GetNetworkId(); err != nil {
+		return fmt.Errorf("failed to get id for INetwork of INetworkConnection %s: %w", info.ID, err)
+	}
+	s.Connections = append(s.Connections, info)
+
+	if i, ok := networks[info.NetworkID]; ok {
+		s.Networks[i].Connections = append(s.Networks[i].Connections, info.ID)
+		return nil
+	}
+	networkInfo, err := NetworkInfoOf(network)
+	if err != nil {
+		return err
+	}
+	networkInfo.Connections = []GUID{info.ID}
+	networks[networkInfo.ID] = len(s.Networks)
+	s.Networks = append(s.Networks, networkInfo)
+	return nil
+}
+
+// connectionInfo returns the state of a network connection, but for the GUID of its network.
+func connectionInfo(conn INetworkConnection) (ConnectionInfo, error) {
+	var info ConnectionInfo
+	var err error
+	if info.ID, err = conn.GetConnectionId(); err != nil {
+		return info, fmt.Errorf("failed to get connection ID for INetworkConnection: %w", err)
+	}
+	if info.AdapterID, err = conn.GetAdapterId(); err != nil {
+		return info, fmt.Errorf("failed to get adapter ID for INetworkConnection %s: %w", info.ID, err)
+	}
+	if info.DomainType, err = conn.GetDomainType(); err != nil {
+		return info, fmt.Errorf("failed to get domain type for INetworkConnection %s: %w", info.ID, err)
+	}
+	if info.Connectivity, err = conn.GetConnectivity(); err != nil {
+		return info, fmt.Errorf("failed to get connectivity for INetworkConnection %s: %w", info.ID, err)
+	}
+	if info.Connected, err = conn.IsConnected(); err != nil {
+		return info, fmt.Errorf("failed to get isConnected on INetworkConnection %s: %w", info.ID, err)
+	}
+	if info.ConnectedToInternet, err = conn.IsConnectedToInternet(); err != nil {
+		return info, fmt.Errorf("failed to get isConnectedToInternet on INetworkConnection %s: %w", info.ID, err)
+	}
+	return info, nil
+}
+
+// NetworkInfoOf returns the state of a network, without its connections.
+func NetworkInfoOf(network INetwork) (NetworkInfo, error) {
+	var info NetworkInfo
+	var err error
+	if info.ID, err = network.GetNetworkId(); err != nil {
+		return info, fmt.Errorf("failed to get id for INetwork: %w", err)
+	}
+	if info.Name, err = network.GetName(); err != nil {
+		return info, fmt.Errorf("failed to get name for INetwork %s: %w", info.ID, err)
+	}
+	if info.Description, err = network.GetDescription(); err != nil {
+		return info, fmt.Errorf("failed to get description for INetwork %s: %w", info.ID, err)
+	}
+	if info.Category, err = network.GetCategory(); err != nil {
+		return info, fmt.Errorf("failed to get category for INetwork %s: %w", info.ID, err)
+	}
+	if info.DomainType, err = network.GetDomainType(); err != nil {
+		return info, fmt.Errorf("failed to get domain type for INetwork %s: %w", info.ID, err)
+	}
+	if info.Connectivity, err = network.GetConnectivity(); err != nil {
+		return info, fmt.Errorf("failed to get connectivity for INetwork %s: %w", info.ID, err)
+	}
+	if info.Connected, err = network.IsConnected(); err != nil {
+		return info, fmt.Errorf("failed to get isConnected on INetwork %s: %w", info.ID, err)
+	}
+	if info.ConnectedToInternet, err = network.IsConnectedToInternet(); err != nil {
+		return info, fmt.Errorf("failed to get isConnectedToInternet on INetwork %s: %w", info.ID, err)
+	}
+	if info.Created, info.LastConnected, err = network.GetTimeCreatedAndConnected(); err != nil {
+		return info, fmt.Errorf("failed to get created/connected timestamps for INetwork %s: %w", info.ID, err)
+	}
+	return info, nil
+}
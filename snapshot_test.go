@@ -0,0 +1,49 @@
+package wnlm_test
+
+import (
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+func TestTakeSnapshot(t *testing.T) {
+	m := fake.NewSample()
+	getNetworkCalls := 0
+	m.SetFault(func(iface, method string) error {
+		if iface == "INetworkConnection" && method == "GetNetwork" {
+			getNetworkCalls++
+		}
+		return nil
+	})
+
+	snapshot, err := wnlm.TakeSnapshot(m)
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	if getNetworkCalls != 2 {
+		t.Errorf("GetNetwork called %d times, want once per connection", getNetworkCalls)
+	}
+
+	home, ok := snapshot.Network(fake.SampleHomeID)
+	if !ok {
+		t.Fatalf("Network(%s) not found", fake.SampleHomeID)
+	}
+	if home.Name != "Home" || home.Category != wnlm.NLMNetworkCategoryPrivate || !home.ConnectedToInternet ||
+		len(home.Connections) != 1 || home.Connections[0] != fake.SampleWiFiID {
+		t.Errorf("Network(%s) = %+v", fake.SampleHomeID, home)
+	}
+	ethernet, ok := snapshot.Connection(fake.SampleEthernetID)
+	if !ok {
+		t.Fatalf("Connection(%s) not found", fake.SampleEthernetID)
+	}
+	if ethernet.NetworkID != fake.SampleOfficeID || ethernet.AdapterID != fake.SampleAdapterID || ethernet.Connected {
+		t.Errorf("Connection(%s) = %+v", fake.SampleEthernetID, ethernet)
+	}
+	if conns := snapshot.ConnectionsOf(fake.SampleOfficeID); len(conns) != 1 || conns[0].ID != fake.SampleEthernetID {
+		t.Errorf("ConnectionsOf(%s) = %+v", fake.SampleOfficeID, conns)
+	}
+	if n := m.Outstanding(); n != 1 {
+		t.Errorf("%d fake objects outstanding, want only the manager", n)
+	}
+}
@@ -0,0 +1,343 @@
+package wnlm
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TrackedObject is a COM object acquired through a Tracker.
+type TrackedObject struct {
+	// ID is the (process-unique) sequence number of the object.
+	ID uint64
+	// Interface is the name of the interface of the object, e.g. "INetwork".
+	Interface string
+	// Acquired is the time at which the object was acquired.
+	Acquired time.Time
+	// Stack is the stack trace of the goroutine which acquired the object.
+	Stack string
+}
+
+// DoubleRelease is a release of an already released TrackedObject.
+type DoubleRelease struct {
+	Object TrackedObject
+	// FirstStack is the stack trace of the goroutine which first released the object.
+	FirstStack string
+	// Stack is the stack trace of the goroutine which released the object again.
+	Stack string
+}
+
+// LeakError is the error returned when tracked COM objects were leaked or released more than once.
+type LeakError struct {
+	Outstanding    []TrackedObject
+	DoubleReleases []DoubleRelease
+}
+
+// Error implements the error interface.
+func (e *LeakError) Error() string {
+	counts := map[string]int{}
+	for _, obj := range e.Outstanding {
+		counts[obj.Interface]++
+	}
+	kinds := make([]string, 0, len(counts))
+	for kind, count := range counts {
+		kinds = append(kinds, fmt.Sprintf("%d %s", count, kind))
+	}
+	sort.Strings(kinds)
+
+	msg := fmt.Sprintf("%d COM object(s) not released", len(e.Outstanding))
+	if len(kinds) > 0 {
+		msg += " (" + strings.Join(kinds, ", ") + ")"
+	}
+	if len(e.DoubleReleases) > 0 {
+		msg += fmt.Sprintf(", %d double release(s)", len(e.DoubleReleases))
+	}
+	return msg
+}
+
+// Tracker records every INetworkListManager, INetwork, INetworkConnection and
+// IEnumNetworkConnections acquired through the INetworkListManager objects it
+// tracks, along with the stack trace of their acquisition, in order to find COM
+// reference leaks. Tracked objects can be released any number of times, but only
+// the first release reaches the underlying object and any further ones are
+// recorded as double releases.
+//
+// Tracking captures a stack trace for every object acquired, so it is meant
+// for debugging and tests rather than for production use.
+type Tracker struct {
+	mu             sync.Mutex
+	nextID         uint64
+	outstanding    map[uint64]*trackedRef
+	doubleReleases []DoubleRelease
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{outstanding: make(map[uint64]*trackedRef)}
+}
+
+// TrackNetworkListManager returns an INetworkListManager which records nlm, and every
+// object obtained from it, in the Tracker. Releasing the returned INetworkListManager
+// releases nlm.
+func (t *Tracker) TrackNetworkListManager(nlm INetworkListManager) INetworkListManager {
+	return &trackedNetworkListManager{trackedRef: t.track("INetworkListManager", nlm.Release), nlm: nlm}
+}
+
+// Outstanding returns the tracked objects which have not been released, in order of acquisition.
+func (t *Tracker) Outstanding() []TrackedObject {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	objects := make([]TrackedObject, 0, len(t.outstanding))
+	for _, ref := range t.outstanding {
+		objects = append(objects, ref.object)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].ID < objects[j].ID })
+	return objects
+}
+
+// DoubleReleases returns the double releases of tracked objects, in the order they happened.
+func (t *Tracker) DoubleReleases() []DoubleRelease {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]DoubleRelease(nil), t.doubleReleases...)
+}
+
+// Check returns a *LeakError if any tracked object is outstanding or was released
+// more than once, and nil otherwise. It is meant to be called once all objects
+// should have been released, e.g. at the end of a test or before a process exits.
+func (t *Tracker) Check() error {
+	outstanding, doubleReleases := t.Outstanding(), t.DoubleReleases()
+	if len(outstanding) == 0 && len(doubleReleases) == 0 {
+		return nil
+	}
+	return &LeakError{Outstanding: outstanding, DoubleReleases: doubleReleases}
+}
+
+// Report writes the outstanding objects and double releases,
+// with their stack traces, to w in a human readable form.
+func (t *Tracker) Report(w io.Writer) error {
+	for _, obj := range t.Outstanding() {
+		if _, err := fmt.Fprintf(w, "%s #%d not released, acquired at %s by:\n%s\n",
+			obj.Interface, obj.ID, obj.Acquired.Format(time.RFC3339Nano), obj.Stack); err != nil {
+			return err
+		}
+	}
+	for _, dr := range t.DoubleReleases() {
+		if _, err := fmt.Fprintf(w, "%s #%d released again by:\n%s\nfirst released by:\n%s\nacquired by:\n%s\n",
+			dr.Object.Interface, dr.Object.ID, dr.Stack, dr.FirstStack, dr.Object.Stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseOutstanding releases the outstanding objects matching the given filter, in
+// reverse order of acquisition, and returns them.
+//
+// Releasing an enumeration releases each of its network connections, some of which
+// may have been released by their user already, and the others are outstanding
+// themselves. Enumerations are therefore only marked as released, and each of
+// their outstanding network connections is released on its own, exactly once.
+func (t *Tracker) releaseOutstanding(match func(TrackedObject) bool) []TrackedObject {
+	t.mu.Lock()
+	refs := make([]*trackedRef, 0, len(t.outstanding))
+	for _, ref := range t.outstanding {
+		if match(ref.object) {
+			refs = append(refs, ref)
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].object.ID > refs[j].object.ID })
+	objects := make([]TrackedObject, 0, len(refs))
+	for _, ref := range refs {
+		if ref.releaseOutstanding(ref.object.Interface != "IEnumNetworkConnections") {
+			objects = append(objects, ref.object)
+		}
+	}
+	return objects
+}
+
+// track records a newly acquired object, released with the given function.
+func (t *Tracker) track(iface string, release func()) *trackedRef {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	ref := &trackedRef{
+		tracker: t,
+		object: TrackedObject{
+			ID:        t.nextID,
+			Interface: iface,
+			Acquired:  time.Now(),
+			Stack:     callerStack(),
+		},
+		release: release,
+	}
+	t.outstanding[ref.object.ID] = ref
+	return ref
+}
+
+// callerStack returns the stack trace of the calling goroutine, without the Tracker's own frames.
+func callerStack() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !isTrackerFrame(frame.Function) {
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// isTrackerFrame returns true if the named function belongs to the Tracker.
+func isTrackerFrame(function string) bool {
+	for _, prefix := range []string{
+		"github.com/adrianosela/wnlm.(*Tracker)",
+		"github.com/adrianosela/wnlm.(*tracked",
+		"github.com/adrianosela/wnlm.trackedNetworkConnections",
+	} {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// trackedRef is the record of a tracked object, and its idempotent release.
+type trackedRef struct {
+	tracker    *Tracker
+	object     TrackedObject
+	release    func()
+	released   bool
+	firstStack string
+}
+
+// Release releases the underlying object the first time it is called,
+// and records a double release every other time.
+func (r *trackedRef) Release() {
+	t := r.tracker
+	t.mu.Lock()
+	if r.released {
+		t.doubleReleases = append(t.doubleReleases, DoubleRelease{Object: r.object, FirstStack: r.firstStack, Stack: callerStack()})
+		t.mu.Unlock()
+		return
+	}
+	r.released = true
+	r.firstStack = callerStack()
+	delete(t.outstanding, r.object.ID)
+	t.mu.Unlock()
+
+	r.release()
+}
+
+// releaseOutstanding marks the object as released, releasing the underlying object
+// too if releaseUnderlying is set, unless it has been released already (which is
+// not recorded as a double release). It returns false if it had been released.
+func (r *trackedRef) releaseOutstanding(releaseUnderlying bool) bool {
+	t := r.tracker
+	t.mu.Lock()
+	if r.released {
+		t.mu.Unlock()
+		return false
+	}
+	r.released = true
+	r.firstStack = callerStack()
+	delete(t.outstanding, r.object.ID)
+	t.mu.Unlock()
+
+	if releaseUnderlying {
+		r.release()
+	}
+	return true
+}
+
+// trackedNetworkListManager is an INetworkListManager recorded by a Tracker.
+type trackedNetworkListManager struct {
+	*trackedRef
+	nlm INetworkListManager
+}
+
+// trackedNetwork is an INetwork recorded by a Tracker.
+type trackedNetwork struct {
+	*trackedRef
+	INetwork
+}
+
+// trackedNetworkConnection is an INetworkConnection recorded by a Tracker.
+type trackedNetworkConnection struct {
+	*trackedRef
+	INetworkConnection
+}
+
+// trackedEnumNetworkConnections is an IEnumNetworkConnections recorded by a Tracker.
+type trackedEnumNetworkConnections struct {
+	*trackedRef
+	IEnumNetworkConnections
+}
+
+// trackedNetworkConnections records an enumeration, and each of its network connections, in t.
+//
+// Releasing the returned enumeration releases each (tracked) network connection rather than
+// the underlying enumeration, so that connections already released by their user are not
+// released again.
+func trackedNetworkConnections(t *Tracker, conns IEnumNetworkConnections) IEnumNetworkConnections {
+	wrapped := make([]INetworkConnection, 0, conns.Size())
+	conns.ForEach(func(_ int, conn INetworkConnection) bool {
+		wrapped = append(wrapped, &trackedNetworkConnection{trackedRef: t.track("INetworkConnection", conn.Release), INetworkConnection: conn})
+		return true
+	})
+	enum := NewNetworkConnectionsFromSlice(wrapped)
+	return &trackedEnumNetworkConnections{trackedRef: t.track("IEnumNetworkConnections", enum.Release), IEnumNetworkConnections: enum}
+}
+
+// GetNetworkConnections returns all network connections for the system.
+func (m *trackedNetworkListManager) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	conns, err := m.nlm.GetNetworkConnections()
+	if err != nil {
+		return nil, err
+	}
+	return trackedNetworkConnections(m.tracker, conns), nil
+}
+
+// GetNetworkConnections returns the network connections of this network.
+func (n *trackedNetwork) GetNetworkConnections() (IEnumNetworkConnections, error) {
+	conns, err := n.INetwork.GetNetworkConnections()
+	if err != nil {
+		return nil, err
+	}
+	return trackedNetworkConnections(n.tracker, conns), nil
+}
+
+// Release releases the INetwork object.
+func (n *trackedNetwork) Release() {
+	n.trackedRef.Release()
+}
+
+// GetNetwork returns the network of this network connection.
+func (nc *trackedNetworkConnection) GetNetwork() (INetwork, error) {
+	network, err := nc.INetworkConnection.GetNetwork()
+	if err != nil {
+		return nil, err
+	}
+	return &trackedNetwork{trackedRef: nc.tracker.track("INetwork", network.Release), INetwork: network}, nil
+}
+
+// Release releases the INetworkConnection object.
+func (nc *trackedNetworkConnection) Release() {
+	nc.trackedRef.Release()
+}
+
+// Release releases the IEnumNetworkConnections object.
+func (nc *trackedEnumNetworkConnections) Release() {
+	nc.trackedRef.Release()
+}
@@ -0,0 +1,153 @@
+package wnlm_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+func TestTrackerReleased(t *testing.T) {
+	m := fake.NewSample()
+	tracker := wnlm.NewTracker()
+	nlm := tracker.TrackNetworkListManager(m)
+
+	conns, conn := firstConnection(t, nlm)
+	network, err := conn.GetNetwork()
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	if got := len(tracker.Outstanding()); got != 5 {
+		t.Errorf("%d outstanding objects, want 5", got)
+	}
+	network.Release()
+	conns.Release()
+	nlm.Release()
+
+	if err := tracker.Check(); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("fake has %d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+}
+
+func TestTrackerLeak(t *testing.T) {
+	tracker := wnlm.NewTracker()
+	nlm := tracker.TrackNetworkListManager(fake.NewSample())
+	defer nlm.Release()
+
+	_, conn := firstConnection(t, nlm)
+	if _, err := conn.GetNetwork(); err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+
+	outstanding := tracker.Outstanding()
+	var kinds []string
+	for _, obj := range outstanding {
+		kinds = append(kinds, obj.Interface)
+		if !strings.Contains(obj.Stack, "TestTrackerLeak") {
+			t.Errorf("%s #%d was not acquired by the test:\n%s", obj.Interface, obj.ID, obj.Stack)
+		}
+	}
+	want := "INetworkListManager INetworkConnection INetworkConnection IEnumNetworkConnections INetwork"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Errorf("outstanding = %s, want %s", got, want)
+	}
+
+	var leakErr *wnlm.LeakError
+	if err := tracker.Check(); !errors.As(err, &leakErr) || len(leakErr.Outstanding) != 5 {
+		t.Fatalf("Check() = %v, want a *LeakError with 5 objects", err)
+	}
+	var report strings.Builder
+	if err := tracker.Report(&report); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if got := strings.Count(report.String(), "not released"); got != 5 {
+		t.Errorf("report lists %d objects, want 5:\n%s", got, report.String())
+	}
+}
+
+func TestTrackerDoubleRelease(t *testing.T) {
+	m := fake.NewSample()
+	tracker := wnlm.NewTracker()
+	nlm := tracker.TrackNetworkListManager(m)
+
+	conns, conn := firstConnection(t, nlm)
+	network, err := conn.GetNetwork()
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	network.Release()
+	network.Release()
+	conns.Release()
+	nlm.Release()
+
+	doubleReleases := tracker.DoubleReleases()
+	if len(doubleReleases) != 1 || doubleReleases[0].Object.Interface != "INetwork" {
+		t.Fatalf("DoubleReleases() = %+v, want the INetwork", doubleReleases)
+	}
+	if doubleReleases[0].FirstStack == "" || doubleReleases[0].Stack == "" {
+		t.Errorf("double release is missing stack traces")
+	}
+	if err := tracker.Check(); err == nil || err.Error() != "0 COM object(s) not released, 1 double release(s)" {
+		t.Errorf("Check() = %v", err)
+	}
+	// only the first release reaches the underlying object
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("fake has %d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+}
+
+func TestSessionLeakTracking(t *testing.T) {
+	m := fake.NewSample()
+	tracker := wnlm.NewTracker()
+	session, err := wnlm.NewSession(wnlm.WithManagerFactory(factory(m)), wnlm.WithLeakTracking(tracker))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	nlm, err := session.NewNetworkListManager()
+	if err != nil {
+		t.Fatalf("NewNetworkListManager failed: %v", err)
+	}
+	if _, err := nlm.GetNetworkConnections(); err != nil {
+		t.Fatalf("GetNetworkConnections failed: %v", err)
+	}
+
+	err = session.Close()
+	var leakErr *wnlm.LeakError
+	if !errors.As(err, &leakErr) {
+		t.Fatalf("Close() = %v, want a *LeakError", err)
+	}
+	if want := "3 COM object(s) not released (1 IEnumNetworkConnections, 2 INetworkConnection)"; err.Error() != want {
+		t.Errorf("Close() = %q, want %q", err, want)
+	}
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("fake has %d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+}
+
+func TestSessionLeakTrackingPartiallyReleased(t *testing.T) {
+	m := fake.NewSample()
+	tracker := wnlm.NewTracker()
+	session, err := wnlm.NewSession(wnlm.WithManagerFactory(factory(m)), wnlm.WithLeakTracking(tracker))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	nlm, err := session.NewNetworkListManager()
+	if err != nil {
+		t.Fatalf("NewNetworkListManager failed: %v", err)
+	}
+	_, conn := firstConnection(t, nlm)
+	conn.Release()
+
+	err = session.Close()
+	if want := "2 COM object(s) not released (1 IEnumNetworkConnections, 1 INetworkConnection)"; err == nil || err.Error() != want {
+		t.Errorf("Close() = %v, want %q", err, want)
+	}
+	if m.Outstanding() != 0 || m.DoubleReleases() != 0 {
+		t.Errorf("fake has %d outstanding references and %d double releases, want none", m.Outstanding(), m.DoubleReleases())
+	}
+}
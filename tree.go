@@ -0,0 +1,84 @@
+package wnlm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteTree writes a human readable tree of the networks in a Snapshot, each followed
+// by its network connections, each followed by its network adapter, annotated with
+// their connectivity per IP family. Connections whose network is not in the Snapshot
+// are written last, under "(unknown network)".
+//
+//	Home {11111111-2222-3333-4444-555555555555} Private, IPv4 Internet, IPv6 Subnet
+//	└── connection {21111111-2222-3333-4444-555555555555} IPv4 Internet
+//	    └── adapter {31111111-2222-3333-4444-555555555555}
+func WriteTree(w io.Writer, snapshot *Snapshot) error {
+	tw := &treeWriter{w: w}
+	for _, network := range snapshot.Networks {
+		annotations := []string{network.Category.String()}
+		if network.DomainType != NLMDomainTypeNonDomainNetwork {
+			annotations = append(annotations, "domain type "+network.DomainType.String())
+		}
+		annotations = append(annotations, formatConnectivityLevels(network.Connectivity))
+		tw.line("", fmt.Sprintf("%s %s %s", network.Name, network.ID, strings.Join(annotations, ", ")))
+		tw.connections(snapshot.ConnectionsOf(network.ID))
+	}
+
+	orphans := []ConnectionInfo{}
+	for _, conn := range snapshot.Connections {
+		if _, ok := snapshot.Network(conn.NetworkID); !ok {
+			orphans = append(orphans, conn)
+		}
+	}
+	if len(orphans) > 0 {
+		tw.line("", "(unknown network)")
+		tw.connections(orphans)
+	}
+	return tw.err
+}
+
+// treeWriter writes the lines of a tree, keeping the first error.
+type treeWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a line of a tree with the given prefix.
+func (tw *treeWriter) line(prefix string, text string) {
+	if tw.err == nil {
+		_, tw.err = fmt.Fprintf(tw.w, "%s%s\n", prefix, text)
+	}
+}
+
+// connections writes the network connections (and adapters) of a network.
+func (tw *treeWriter) connections(conns []ConnectionInfo) {
+	for i, conn := range conns {
+		branch, indent := "├── ", "│   "
+		if i == len(conns)-1 {
+			branch, indent = "└── ", "    "
+		}
+		text := fmt.Sprintf("connection %s %s", conn.ID, formatConnectivityLevels(conn.Connectivity))
+		if conn.DomainType != NLMDomainTypeNonDomainNetwork {
+			text += ", domain type " + conn.DomainType.String()
+		}
+		tw.line(branch, text)
+		tw.line(indent+"└── ", fmt.Sprintf("adapter %s", conn.AdapterID))
+	}
+}
+
+// formatConnectivityLevels returns the connectivity level of each
+// connected IP family, e.g. "IPv4 Internet, IPv6 Subnet".
+func formatConnectivityLevels(connectivity NLMConnectivity) string {
+	levels := []string{}
+	for _, family := range []IPFamily{IPFamilyIPv4, IPFamilyIPv6} {
+		if level := connectivity.Level(family); level != ConnectivityLevelDisconnected {
+			levels = append(levels, fmt.Sprintf("%s %s", family, level))
+		}
+	}
+	if len(levels) == 0 {
+		return ConnectivityLevelDisconnected.String()
+	}
+	return strings.Join(levels, ", ")
+}
@@ -0,0 +1,106 @@
+package wnlm_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+var update = flag.Bool("update", false, "update the golden files of tests")
+
+// assertGolden compares got to the named golden file in testdata, or updates it with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestWriteTree(t *testing.T) {
+	secondWifiID := wnlm.MustParseGUID("{DDDDDDDD-DDDD-DDDD-DDDD-DDDDDDDDDDDD}")
+	unknownID := wnlm.MustParseGUID("{EEEEEEEE-EEEE-EEEE-EEEE-EEEEEEEEEEEE}")
+	tests := []struct {
+		name     string
+		snapshot *wnlm.Snapshot
+	}{
+		{"tree_empty", &wnlm.Snapshot{}},
+		{
+			name: "tree",
+			snapshot: func() *wnlm.Snapshot {
+				snapshot, err := wnlm.TakeSnapshot(fake.NewSample())
+				if err != nil {
+					t.Fatalf("TakeSnapshot failed: %v", err)
+				}
+				return snapshot
+			}(),
+		},
+		{
+			name: "tree_orphans",
+			snapshot: &wnlm.Snapshot{
+				Networks: []wnlm.NetworkInfo{{
+					ID:           fake.SampleHomeID,
+					Name:         "Home",
+					Category:     wnlm.NLMNetworkCategoryPrivate,
+					Connectivity: wnlm.NLMConnectivityIPv4Internet | wnlm.NLMConnectivityIPv6Subnet,
+				}},
+				Connections: []wnlm.ConnectionInfo{
+					{ID: fake.SampleWiFiID, AdapterID: fake.SampleAdapterID, NetworkID: fake.SampleHomeID, Connectivity: wnlm.NLMConnectivityIPv4Internet},
+					{ID: secondWifiID, AdapterID: fake.SampleAdapterID, NetworkID: fake.SampleHomeID, Connectivity: wnlm.NLMConnectivityIPv6Subnet},
+					{ID: fake.SampleEthernetID, AdapterID: fake.SampleAdapterID, NetworkID: unknownID, DomainType: wnlm.NLMDomainTypeDomainNetwork, Connectivity: wnlm.NLMConnectivityIPv4NoTraffic},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := wnlm.WriteTree(&b, test.snapshot); err != nil {
+				t.Fatalf("WriteTree failed: %v", err)
+			}
+			assertGolden(t, test.name, b.Bytes())
+		})
+	}
+}
+
+// failingWriter is an io.Writer failing after n writes.
+type failingWriter struct {
+	n int
+}
+
+var errWrite = errors.New("disk full")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errWrite
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestWriteTreeError(t *testing.T) {
+	snapshot, err := wnlm.TakeSnapshot(fake.NewSample())
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	w := &failingWriter{n: 2}
+	if err := wnlm.WriteTree(w, snapshot); !errors.Is(err, errWrite) {
+		t.Errorf("WriteTree = %v, want %v", err, errWrite)
+	}
+}
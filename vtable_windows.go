@@ -0,0 +1,162 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/adrianosela/wnlm/pkg/hresult"
+	"github.com/adrianosela/wnlm/pkg/oleconv"
+	"github.com/go-ole/go-ole"
+)
+
+// vtableCall calls the method at the given vtable slot of a COM object and returns its HRESULT.
+func vtableCall(this *ole.IDispatch, method uintptr, args ...uintptr) hresult.HRESULT {
+	r, _, _ := syscall.SyscallN(method, append([]uintptr{uintptr(unsafe.Pointer(this))}, args...)...)
+	return hresult.FromUintptr(r)
+}
+
+// useIDispatch returns true if a direct vtable call failed in a way that
+// warrants retrying the call through (DISPID-cached) IDispatch invocation instead.
+func useIDispatch(hr hresult.HRESULT) bool {
+	return hr == hresult.E_NOTIMPL
+}
+
+// getBSTR calls a method of the form HRESULT Method(BSTR*), falling back to IDispatch.
+func getBSTR(this *ole.IDispatch, iid *ole.GUID, method uintptr, name string) (string, error) {
+	var bstr *uint16
+	hr := vtableCall(this, method, uintptr(unsafe.Pointer(&bstr)))
+	if useIDispatch(hr) {
+		res, err := invoke(this, iid, name, ole.DISPATCH_METHOD)
+		if err != nil {
+			return "", err
+		}
+		defer res.Clear()
+		return res.ToString(), nil
+	}
+	if err := hresult.Check(hr); err != nil {
+		return "", err
+	}
+	defer ole.SysFreeString((*int16)(unsafe.Pointer(bstr)))
+	return oleconv.BSTRToString(bstr), nil
+}
+
+// putBSTR calls a method of the form HRESULT Method(BSTR), falling back to IDispatch.
+func putBSTR(this *ole.IDispatch, iid *ole.GUID, method uintptr, name string, value string) error {
+	bstr := ole.SysAllocStringLen(value)
+	if bstr == nil {
+		return hresult.E_OUTOFMEMORY
+	}
+	defer ole.SysFreeString(bstr)
+
+	hr := vtableCall(this, method, uintptr(unsafe.Pointer(bstr)))
+	if useIDispatch(hr) {
+		res, err := invoke(this, iid, name, ole.DISPATCH_METHOD, value)
+		if err != nil {
+			return err
+		}
+		return res.Clear()
+	}
+	return hresult.Check(hr)
+}
+
+// getInt32 calls a method of the form HRESULT Method(LONG*) (or any other
+// 32-bit enumeration pointer), falling back to IDispatch.
+func getInt32(this *ole.IDispatch, iid *ole.GUID, method uintptr, name string) (int32, error) {
+	var value int32
+	hr := vtableCall(this, method, uintptr(unsafe.Pointer(&value)))
+	if useIDispatch(hr) {
+		res, err := invoke(this, iid, name, ole.DISPATCH_METHOD)
+		if err != nil {
+			return 0, err
+		}
+		defer res.Clear()
+		valueAny := res.Value()
+		valueInt32, ok := valueAny.(int32)
+		if !ok {
+			return 0, fmt.Errorf("unexpected result type for %s method: expected int32 but got %T", name, valueAny)
+		}
+		return valueInt32, nil
+	}
+	if err := hresult.Check(hr); err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+// putInt32 calls a method of the form HRESULT Method(LONG) (or any other
+// 32-bit enumeration), falling back to IDispatch.
+func putInt32(this *ole.IDispatch, iid *ole.GUID, method uintptr, name string, value int32) error {
+	hr := vtableCall(this, method, uintptr(value))
+	if useIDispatch(hr) {
+		res, err := invoke(this, iid, name, ole.DISPATCH_METHOD, value)
+		if err != nil {
+			return err
+		}
+		return res.Clear()
+	}
+	return hresult.Check(hr)
+}
+
+// getVariantBool calls a property getter of the form HRESULT Property(VARIANT_BOOL*),
+// falling back to IDispatch.
+func getVariantBool(this *ole.IDispatch, iid *ole.GUID, method uintptr, name string) (bool, error) {
+	var value oleconv.VariantBool
+	hr := vtableCall(this, method, uintptr(unsafe.Pointer(&value)))
+	if useIDispatch(hr) {
+		res, err := invoke(this, iid, name, ole.DISPATCH_PROPERTYGET)
+		if err != nil {
+			return false, err
+		}
+		defer res.Clear()
+		valueAny := res.Value()
+		valueBool, ok := valueAny.(bool)
+		if !ok {
+			return false, fmt.Errorf("unexpected result type for %s property: expected bool but got %T", name, valueAny)
+		}
+		return valueBool, nil
+	}
+	if err := hresult.Check(hr); err != nil {
+		return false, err
+	}
+	return value.Bool(), nil
+}
+
+// getIDispatch calls a method of the form HRESULT Method(IDispatch**) (or any
+// other dual interface pointer), falling back to IDispatch. The caller is
+// responsible for releasing the returned object.
+func getIDispatch(this *ole.IDispatch, iid *ole.GUID, method uintptr, name string) (*ole.IDispatch, error) {
+	var idispatch *ole.IDispatch
+	hr := vtableCall(this, method, uintptr(unsafe.Pointer(&idispatch)))
+	if useIDispatch(hr) {
+		res, err := invoke(this, iid, name, ole.DISPATCH_METHOD)
+		if err != nil {
+			return nil, err
+		}
+		idispatch = res.ToIDispatch()
+		if idispatch == nil {
+			defer res.Clear()
+			return nil, fmt.Errorf("unexpected result type for %s method: expected IDispatch but got %T", name, res.Value())
+		}
+		return idispatch, nil
+	}
+	if err := hresult.Check(hr); err != nil {
+		return nil, err
+	}
+	if idispatch == nil {
+		return nil, fmt.Errorf("unexpected nil result for %s method", name)
+	}
+	return idispatch, nil
+}
+
+// getGUID calls a method of the form HRESULT Method(GUID*). There is no IDispatch
+// fallback since GUIDs are not representable as VARIANTs.
+func getGUID(this *ole.IDispatch, method uintptr) (GUID, error) {
+	var guid GUID
+	if err := hresult.Check(vtableCall(this, method, uintptr(unsafe.Pointer(&guid)))); err != nil {
+		return GUID{}, err
+	}
+	return guid, nil
+}
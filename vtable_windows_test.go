@@ -0,0 +1,76 @@
+//go:build windows
+
+package wnlm
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/go-ole/go-ole"
+)
+
+// benchmarkNetworkListManager returns a NetworkListManager for benchmarking calls
+// made on the calling goroutine, which is locked to its (COM-initialized) thread.
+func benchmarkNetworkListManager(b *testing.B) *iNetworkListManager {
+	runtime.LockOSThread()
+	if err := coInitializeEx(ole.COINIT_MULTITHREADED); err != nil {
+		runtime.UnlockOSThread()
+		b.Skipf("failed to initialize COM: %v", err)
+	}
+	nlm, err := NewNetworkListManager(WithPreResolvedDISPIDs())
+	if err != nil {
+		ole.CoUninitialize()
+		runtime.UnlockOSThread()
+		b.Skipf("failed to create NetworkListManager: %v", err)
+	}
+	b.Cleanup(func() {
+		nlm.Release()
+		ole.CoUninitialize()
+		runtime.UnlockOSThread()
+	})
+	return nlm.(*iNetworkListManager)
+}
+
+func BenchmarkIsConnectedVtable(b *testing.B) {
+	nlm := benchmarkNetworkListManager(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := getVariantBool(nlm.idispatch, iidINetworkListManager, nlm.vtable().IsConnected, "IsConnected"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIsConnectedIDispatch(b *testing.B) {
+	nlm := benchmarkNetworkListManager(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res, err := invoke(nlm.idispatch, iidINetworkListManager, "IsConnected", ole.DISPATCH_PROPERTYGET)
+		if err != nil {
+			b.Fatal(err)
+		}
+		res.Clear()
+	}
+}
+
+func BenchmarkGetConnectivityVtable(b *testing.B) {
+	nlm := benchmarkNetworkListManager(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := getInt32(nlm.idispatch, iidINetworkListManager, nlm.vtable().GetConnectivity, "GetConnectivity"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetConnectivityIDispatch(b *testing.B) {
+	nlm := benchmarkNetworkListManager(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res, err := invoke(nlm.idispatch, iidINetworkListManager, "GetConnectivity", ole.DISPATCH_METHOD)
+		if err != nil {
+			b.Fatal(err)
+		}
+		res.Clear()
+	}
+}
@@ -0,0 +1,205 @@
+package wnlm
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Watcher is an EventSource which finds changes by periodically taking a Snapshot
+// of an INetworkListManager and comparing it to the previous one (see DiffSnapshots).
+type Watcher struct {
+	nlm      INetworkListManager
+	interval time.Duration
+	options  watcherOptions
+
+	mu          sync.Mutex
+	last        *Snapshot
+	nextID      int
+	subscribers map[int]func(Event)
+}
+
+var _ EventSource = (*Watcher)(nil)
+
+// WatcherOption configures a Watcher created with NewWatcher.
+type WatcherOption func(*watcherOptions)
+
+// watcherOptions holds the configuration set by WatcherOptions.
+type watcherOptions struct {
+	onPollError func(error)
+}
+
+// WithPollErrorHandler sets the function called with the error of every failed poll
+// made by Run, which carries on polling regardless. By default, errors are ignored.
+func WithPollErrorHandler(handle func(error)) WatcherOption {
+	return func(o *watcherOptions) { o.onPollError = handle }
+}
+
+// NewWatcher returns a Watcher polling nlm at the given interval once running (see Run).
+func NewWatcher(nlm INetworkListManager, interval time.Duration, opts ...WatcherOption) *Watcher {
+	var options watcherOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &Watcher{nlm: nlm, interval: interval, options: options, subscribers: make(map[int]func(Event))}
+}
+
+// Subscribe calls handle with every Event found, until the returned function is called.
+func (w *Watcher) Subscribe(handle func(Event)) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = handle
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Snapshot returns the last Snapshot taken, or nil if none was taken yet.
+func (w *Watcher) Snapshot() *Snapshot {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.last
+}
+
+// Poll takes a Snapshot and delivers the Events found since the previous one to
+// subscribers, and returns them. The first Snapshot taken yields no Events.
+func (w *Watcher) Poll() ([]Event, error) {
+	snapshot, err := TakeSnapshot(w.nlm)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	var events []Event
+	if w.last != nil {
+		events = DiffSnapshots(w.last, snapshot)
+	}
+	w.last = snapshot
+	subscribers := make([]func(Event), 0, len(w.subscribers))
+	for _, handle := range w.subscribers {
+		subscribers = append(subscribers, handle)
+	}
+	w.mu.Unlock()
+
+	for _, event := range events {
+		for _, handle := range subscribers {
+			handle(event)
+		}
+	}
+	return events, nil
+}
+
+// Run polls for changes until ctx is done, and returns its error. A failed poll is
+// reported to the handler set with WithPollErrorHandler, and polling carries on at
+// the next interval, comparing the next Snapshot taken to the last one that was.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Poll(); err != nil && w.options.onPollError != nil {
+			w.options.onPollError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// DiffSnapshots returns the Events describing the changes from one Snapshot to the
+// next: networks, then network connections, that were deleted, added or changed.
+// Every Event has the Time of the newer Snapshot.
+func DiffSnapshots(from, to *Snapshot) []Event {
+	events := []Event{}
+	emit := func(event Event) {
+		event.Time = to.Time
+		events = append(events, event)
+	}
+
+	for _, old := range from.Networks {
+		if _, ok := to.Network(old.ID); !ok {
+			emit(Event{Kind: EventNetworkDeleted, ID: old.ID, NetworkID: old.ID, Name: old.Name, PreviousConnectivity: old.Connectivity})
+		}
+	}
+	for _, network := range to.Networks {
+		old, ok := from.Network(network.ID)
+		if !ok {
+			emit(Event{Kind: EventNetworkAdded, ID: network.ID, NetworkID: network.ID, Name: network.Name, Connectivity: network.Connectivity})
+			continue
+		}
+		if old.Connectivity != network.Connectivity {
+			emit(Event{
+				Kind:                 EventNetworkConnectivityChanged,
+				ID:                   network.ID,
+				NetworkID:            network.ID,
+				Name:                 network.Name,
+				Connectivity:         network.Connectivity,
+				PreviousConnectivity: old.Connectivity,
+			})
+		}
+		if properties := networkPropertyChanges(old, network); properties != 0 {
+			emit(Event{Kind: EventNetworkPropertyChanged, ID: network.ID, NetworkID: network.ID, Name: network.Name, Properties: properties})
+		}
+	}
+
+	nameOf := func(s *Snapshot, networkID GUID) string {
+		network, _ := s.Network(networkID)
+		return network.Name
+	}
+	for _, old := range from.Connections {
+		if _, ok := to.Connection(old.ID); !ok {
+			emit(Event{Kind: EventConnectionDeleted, ID: old.ID, NetworkID: old.NetworkID, Name: nameOf(from, old.NetworkID), PreviousConnectivity: old.Connectivity})
+		}
+	}
+	for _, conn := range to.Connections {
+		old, ok := from.Connection(conn.ID)
+		if !ok {
+			emit(Event{Kind: EventConnectionAdded, ID: conn.ID, NetworkID: conn.NetworkID, Name: nameOf(to, conn.NetworkID), Connectivity: conn.Connectivity})
+			continue
+		}
+		if old.Connectivity != conn.Connectivity {
+			emit(Event{
+				Kind:                 EventConnectionConnectivityChanged,
+				ID:                   conn.ID,
+				NetworkID:            conn.NetworkID,
+				Name:                 nameOf(to, conn.NetworkID),
+				Connectivity:         conn.Connectivity,
+				PreviousConnectivity: old.Connectivity,
+			})
+		}
+		if old.AdapterID != conn.AdapterID || old.NetworkID != conn.NetworkID || old.DomainType != conn.DomainType {
+			emit(Event{Kind: EventConnectionPropertyChanged, ID: conn.ID, NetworkID: conn.NetworkID, Name: nameOf(to, conn.NetworkID)})
+		}
+	}
+	return events
+}
+
+// networkPropertyChanges returns the properties which differ between two states of a network.
+func networkPropertyChanges(from, to NetworkInfo) NLMNetworkPropertyChange {
+	var properties NLMNetworkPropertyChange
+	if from.Name != to.Name {
+		properties |= NLMNetworkPropertyChangeName
+	}
+	if from.Description != to.Description {
+		properties |= NLMNetworkPropertyChangeDescription
+	}
+	if from.Category != to.Category {
+		properties |= NLMNetworkPropertyChangeCategoryValue
+	}
+	if !slices.Equal(sortedGUIDs(from.Connections), sortedGUIDs(to.Connections)) {
+		properties |= NLMNetworkPropertyChangeConnection
+	}
+	return properties
+}
+
+// sortedGUIDs returns a sorted copy of a list of GUIDs.
+func sortedGUIDs(ids []GUID) []GUID {
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, GUID.Compare)
+	return sorted
+}
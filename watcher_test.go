@@ -0,0 +1,134 @@
+package wnlm_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adrianosela/wnlm"
+	"github.com/adrianosela/wnlm/fake"
+)
+
+var lanID = wnlm.MustParseGUID("{33333333-3333-3333-3333-333333333333}")
+
+// lanStep is a script step adding a connected LAN network, with a connection.
+var lanStep = fake.Step{
+	SetNetwork:    &fake.Network{ID: lanID, Name: "LAN", Category: wnlm.NLMNetworkCategoryPrivate, Connectivity: wnlm.NLMConnectivityIPv4LocalNetwork},
+	SetConnection: &fake.Connection{ID: lanID, AdapterID: fake.SampleAdapterID, NetworkID: lanID, Connectivity: wnlm.NLMConnectivityIPv4LocalNetwork},
+}
+
+func TestWatcherPoll(t *testing.T) {
+	m := fake.NewSample()
+	watcher := wnlm.NewWatcher(m, time.Hour)
+	var delivered []wnlm.Event
+	unsubscribe := watcher.Subscribe(func(event wnlm.Event) { delivered = append(delivered, event) })
+	defer unsubscribe()
+
+	if events, err := watcher.Poll(); err != nil || len(events) != 0 {
+		t.Fatalf("first Poll = %v, %v, want no events", events, err)
+	}
+	m.Apply(lanStep)
+	m.UpdateNetwork(fake.SampleOfficeID, func(n *fake.Network) { n.Name = "HQ" })
+	events, err := watcher.Poll()
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	kinds := []wnlm.EventKind{}
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	want := []wnlm.EventKind{wnlm.EventNetworkPropertyChanged, wnlm.EventNetworkAdded, wnlm.EventConnectionAdded}
+	if len(kinds) != len(want) || kinds[0] != want[0] || kinds[1] != want[1] || kinds[2] != want[2] {
+		t.Fatalf("Poll events = %v, want %v", kinds, want)
+	}
+	if events[0].ID != fake.SampleOfficeID || !events[0].Properties.Has(wnlm.NLMNetworkPropertyChangeName) {
+		t.Errorf("property change = %+v, want the name of the office network", events[0])
+	}
+	if len(delivered) != len(events) {
+		t.Errorf("%d events delivered to subscribers, want %d", len(delivered), len(events))
+	}
+	if snapshot := watcher.Snapshot(); snapshot == nil || len(snapshot.Networks) != 3 {
+		t.Errorf("Snapshot() = %+v, want the last one taken", snapshot)
+	}
+}
+
+func TestWatcherRunKeepsPolling(t *testing.T) {
+	m := fake.NewSample()
+	errPoll := errors.New("server went away")
+	var failing atomic.Bool
+	m.SetFault(func(iface, method string) error {
+		if failing.Load() && method == "GetNetworkConnections" {
+			return errPoll
+		}
+		return nil
+	})
+	errs := make(chan error, 100)
+	watcher := wnlm.NewWatcher(m, time.Millisecond, wnlm.WithPollErrorHandler(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+	events := make(chan wnlm.Event, 100)
+	watcher.Subscribe(func(event wnlm.Event) { events <- event })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	failing.Store(true)
+	go func() { done <- watcher.Run(ctx) }()
+
+	waitError := func() {
+		t.Helper()
+		select {
+		case err := <-errs:
+			if !errors.Is(err, errPoll) {
+				t.Fatalf("poll error = %v, want %v", err, errPoll)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no poll error reported")
+		}
+	}
+	waitEvent := func(kind wnlm.EventKind) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case event := <-events:
+				if event.Kind == kind && event.ID == lanID {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("no %s event for %s", kind, lanID)
+			}
+		}
+	}
+
+	waitError()
+	failing.Store(false)
+	for watcher.Snapshot() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	if err := m.Play(ctx, []fake.Step{lanStep}); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	waitEvent(wnlm.EventNetworkAdded)
+
+	// changes made while polls fail are found once they succeed again
+	failing.Store(true)
+	waitError()
+	if err := m.Play(ctx, []fake.Step{{RemoveNetwork: &lanID}}); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	failing.Store(false)
+	waitEvent(wnlm.EventNetworkDeleted)
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+	if n := m.Outstanding(); n != 1 {
+		t.Errorf("%d fake objects outstanding, want only the manager", n)
+	}
+}